@@ -2,22 +2,31 @@ package account
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/rizface/quora/jobs"
+	"github.com/rizface/quora/mailer"
+	"github.com/rizface/quora/ratelimit"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type Feature struct {
 	Handler *Handler
+	limiter *ratelimit.Limiter
 }
 
-func NewFeature(r *chi.Mux, sql *sql.DB, tracer trace.Tracer) *Feature {
+func NewFeature(r *chi.Mux, sql *sql.DB, tracer trace.Tracer, limiter *ratelimit.Limiter, runner *jobs.Runner, sender mailer.Sender) *Feature {
 	repo := NewRepository(sql, tracer)
-	svc := NewService(repo, tracer)
+	notifier := NewNotifier(repo, runner, sender, tracer)
+	svc := NewService(repo, notifier, tracer)
+
+	notifier.Register()
 	handler := NewHandler(r, svc, tracer)
 
 	return &Feature{
 		Handler: handler,
+		limiter: limiter,
 	}
 }
 
@@ -25,7 +34,8 @@ func (f *Feature) RegisterRoutes() {
 	r := f.Handler.r
 
 	r.Route("/accounts", func(r chi.Router) {
-		r.Post("/", f.Handler.Register)
-		r.Post("/login", f.Handler.Login)
+		r.With(f.limiter.Limit("register", ratelimit.Rate{Limit: 5, Period: time.Hour})).Post("/", f.Handler.Register)
+		// keyed by IP, slows password guessing down
+		r.With(f.limiter.Limit("login", ratelimit.Rate{Limit: 5, Period: time.Minute})).Post("/login", f.Handler.Login)
 	})
 }
@@ -1,10 +1,40 @@
 package account
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
 	ErrEmailIsUsed     = errors.New("email is used")
 	ErrUsernameIsUsed  = errors.New("username is used")
 	ErrAccountNotFound = errors.New("account not found")
 	ErrCredential      = errors.New("wrong email / password")
+	ErrLoginDelayed    = errors.New("too many failed logins")
+	ErrLoginLocked     = errors.New("login is locked")
 )
+
+// LoginBlockedError is returned when an account or an IP failed to log in too
+// many times. It matches ErrLoginLocked or ErrLoginDelayed with errors.Is.
+type LoginBlockedError struct {
+	Scope      string
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e LoginBlockedError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("%s is locked after too many failed logins", e.Scope)
+	}
+
+	return "too many failed logins, try again later"
+}
+
+func (e LoginBlockedError) Is(target error) bool {
+	if e.Locked {
+		return target == ErrLoginLocked
+	}
+
+	return target == ErrLoginDelayed
+}
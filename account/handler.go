@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/rizface/quora/account/value"
+	"github.com/rizface/quora/identifier"
 	"github.com/rizface/quora/stdres"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -38,7 +41,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	)
 
 	if err = json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code:    http.StatusBadRequest,
 			TraceId: span.SpanContext().TraceID().String(),
 			Info:    "invalid request body",
@@ -50,7 +53,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	account, err := h.svc.Register(ctx, payload)
 
 	if errors.As(err, &validation.Errors{}) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code:    http.StatusBadRequest,
 			Data:    err,
 			TraceId: span.SpanContext().TraceID().String(),
@@ -61,7 +64,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if errors.Is(err, ErrEmailIsUsed) || errors.Is(err, ErrUsernameIsUsed) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code:    http.StatusConflict,
 			Data:    account,
 			TraceId: span.SpanContext().TraceID().String(),
@@ -72,7 +75,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code:    http.StatusInternalServerError,
 			TraceId: span.SpanContext().TraceID().String(),
 			Info:    err.Error(),
@@ -84,7 +87,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stdres.Writer(w, stdres.Response{
+	stdres.Writer(w, r, stdres.Response{
 		Code:    http.StatusOK,
 		Data:    map[string]interface{}{"doc": account},
 		TraceId: span.SpanContext().TraceID().String(),
@@ -99,7 +102,7 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var payload value.AccountPayload
 
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code:    http.StatusBadRequest,
 			TraceId: span.SpanContext().TraceID().String(),
 			Info:    "invalid body request",
@@ -108,10 +111,28 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.svc.Login(ctx, payload)
+	result, err := h.svc.Login(ctx, payload, identifier.ClientIP(r))
+
+	var blocked LoginBlockedError
+	if errors.As(err, &blocked) {
+		code := http.StatusTooManyRequests
+		if blocked.Locked {
+			code = http.StatusLocked
+		}
+
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(blocked.RetryAfter.Seconds()))))
+
+		stdres.Writer(w, r, stdres.Response{
+			Code:    code,
+			TraceId: span.SpanContext().TraceID().String(),
+			Info:    err.Error(),
+		})
+
+		return
+	}
 
 	if errors.Is(err, ErrAccountNotFound) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code:    http.StatusNotFound,
 			TraceId: span.SpanContext().TraceID().String(),
 			Info:    err.Error(),
@@ -121,7 +142,7 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if errors.Is(err, ErrCredential) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code:    http.StatusUnauthorized,
 			TraceId: span.SpanContext().TraceID().String(),
 			Info:    err.Error(),
@@ -131,7 +152,7 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code:    http.StatusInternalServerError,
 			TraceId: span.SpanContext().TraceID().String(),
 			Info:    err.Error(),
@@ -143,7 +164,7 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stdres.Writer(w, stdres.Response{
+	stdres.Writer(w, r, stdres.Response{
 		Code:    http.StatusOK,
 		Data:    result,
 		TraceId: span.SpanContext().TraceID().String(),
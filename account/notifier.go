@@ -0,0 +1,85 @@
+package account
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rizface/quora/account/value"
+	"github.com/rizface/quora/jobs"
+	jobvalue "github.com/rizface/quora/jobs/value"
+	"github.com/rizface/quora/mailer"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type lockedJob struct {
+	AccountId   string    `json:"accountId"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
+
+func (lockedJob) Kind() string { return "account.locked" }
+
+// Notifier emails the owners of the locked accounts through the job runner, so
+// the login does not wait for the email and a failed email is retried.
+type Notifier struct {
+	repo   *Repository
+	runner *jobs.Runner
+	sender mailer.Sender
+	tracer trace.Tracer
+}
+
+func NewNotifier(repo *Repository, runner *jobs.Runner, sender mailer.Sender, tracer trace.Tracer) *Notifier {
+	return &Notifier{
+		repo:   repo,
+		runner: runner,
+		sender: sender,
+		tracer: tracer,
+	}
+}
+
+func (n *Notifier) Register() {
+	n.runner.Queue(mailer.Queue, 2)
+
+	jobs.Register(n.runner, n.send)
+}
+
+// Locked enqueues the email with the transaction of the lock
+func (n *Notifier) Locked(ctx context.Context, tx *sql.Tx, accountId string, until time.Time) error {
+	job := lockedJob{
+		AccountId:   accountId,
+		LockedUntil: until,
+	}
+
+	return n.runner.EnqueueTx(ctx, tx, job, jobvalue.Options{
+		Queue:     mailer.Queue,
+		UniqueKey: fmt.Sprintf("%s:%s:%d", job.Kind(), accountId, until.Unix()),
+	})
+}
+
+func (n *Notifier) send(ctx context.Context, job lockedJob) error {
+	ctx, span := n.tracer.Start(ctx, "account.Notifier.send")
+	defer span.End()
+
+	account, err := n.repo.FindById(ctx, job.AccountId)
+
+	// deleted since it was locked
+	if errors.Is(err, ErrAccountNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	msg, err := RenderLocked(value.LockedNotice{
+		Account:     account,
+		LockedUntil: job.LockedUntil,
+	})
+	if err != nil {
+		return err
+	}
+
+	return n.sender.Send(ctx, msg)
+}
@@ -0,0 +1,38 @@
+package account
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+
+	"github.com/rizface/quora/account/value"
+	"github.com/rizface/quora/mailer"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var (
+	lockedHtmlTemplate = htmltemplate.Must(htmltemplate.ParseFS(templates, "templates/locked.html"))
+	lockedTextTemplate = texttemplate.Must(texttemplate.ParseFS(templates, "templates/locked.txt"))
+)
+
+func RenderLocked(n value.LockedNotice) (mailer.Message, error) {
+	var html, text bytes.Buffer
+
+	if err := lockedHtmlTemplate.Execute(&html, n); err != nil {
+		return mailer.Message{}, err
+	}
+
+	if err := lockedTextTemplate.Execute(&text, n); err != nil {
+		return mailer.Message{}, err
+	}
+
+	return mailer.Message{
+		To:      n.Account.Email,
+		Subject: "Your account was locked",
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
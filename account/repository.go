@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/rizface/quora/account/value"
+	"github.com/rizface/quora/audit"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -120,3 +122,165 @@ func (r *Repository) FindByEmail(ctx context.Context, account value.AccountEntit
 
 	return account, nil
 }
+
+func (r *Repository) FindById(ctx context.Context, id string) (value.AccountEntity, error) {
+	ctx, span := r.tracer.Start(ctx, "account.Repository.FindById")
+	defer span.End()
+
+	var (
+		account value.AccountEntity
+		query   = `SELECT id, username, email FROM accounts WHERE id = $1`
+	)
+
+	err := r.sql.QueryRowContext(ctx, query, id).Scan(&account.Id, &account.Username, &account.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return account, ErrAccountNotFound
+	}
+
+	return account, err
+}
+
+// GetFailure returns a LoginFailure without failures when the scope has not
+// failed to log in.
+func (r *Repository) GetFailure(ctx context.Context, scope, key string) (value.LoginFailure, error) {
+	ctx, span := r.tracer.Start(ctx, "account.Repository.GetFailure")
+	defer span.End()
+
+	var (
+		f     = value.LoginFailure{Scope: scope, Key: key}
+		query = `
+			SELECT failures, last_failed_at, locked_until FROM login_failures WHERE scope = $1 AND key = $2
+		`
+	)
+
+	err := r.sql.QueryRowContext(ctx, query, scope, key).Scan(&f.Failures, &f.LastFailedAt, &f.LockedUntil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return f, nil
+	}
+
+	return f, err
+}
+
+// RecordFailure counts a failed login, the count starts over when the last
+// failure is older than the window.
+func (r *Repository) RecordFailure(ctx context.Context, scope, key string, now time.Time, window time.Duration) (value.LoginFailure, error) {
+	ctx, span := r.tracer.Start(ctx, "account.Repository.RecordFailure")
+	defer span.End()
+
+	var (
+		f       = value.LoginFailure{Scope: scope, Key: key}
+		command = `
+			INSERT INTO login_failures (scope, key, failures, last_failed_at) VALUES ($1, $2, 1, $3)
+			ON CONFLICT (scope, key) DO UPDATE SET
+				failures = CASE WHEN login_failures.last_failed_at < $4 THEN 1 ELSE login_failures.failures + 1 END,
+				last_failed_at = EXCLUDED.last_failed_at
+			RETURNING failures, last_failed_at, locked_until
+		`
+	)
+
+	err := r.sql.
+		QueryRowContext(ctx, command, scope, key, now, now.Add(-window)).
+		Scan(&f.Failures, &f.LastFailedAt, &f.LockedUntil)
+
+	return f, err
+}
+
+// Lock locks the scope out until the given time. Concurrent failures may all
+// reach the threshold, only the one that locks writes the audit log and calls
+// onLocked, in the same transaction.
+func (r *Repository) Lock(ctx context.Context, f value.LoginFailure, until time.Time, ip string, onLocked func(tx *sql.Tx) error) error {
+	ctx, span := r.tracer.Start(ctx, "account.Repository.Lock")
+	defer span.End()
+
+	tx, err := r.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	command := `
+		UPDATE login_failures SET locked_until = $3 WHERE scope = $1 AND key = $2 AND locked_until IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, command, f.Scope, f.Key, until)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return err
+	}
+
+	err = audit.Record(ctx, tx, audit.Entry{
+		Action:     f.Scope + ".locked",
+		TargetType: f.Scope,
+		TargetId:   f.Key,
+		IP:         ip,
+		Metadata: map[string]interface{}{
+			"failures":    f.Failures,
+			"lockedUntil": until,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := onLocked(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Unlock lifts an expired lock and forgets the failures of the scope.
+func (r *Repository) Unlock(ctx context.Context, f value.LoginFailure, now time.Time, ip string) error {
+	ctx, span := r.tracer.Start(ctx, "account.Repository.Unlock")
+	defer span.End()
+
+	tx, err := r.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	command := `
+		DELETE FROM login_failures WHERE scope = $1 AND key = $2 AND locked_until <= $3
+	`
+
+	result, err := tx.ExecContext(ctx, command, f.Scope, f.Key, now)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return err
+	}
+
+	err = audit.Record(ctx, tx, audit.Entry{
+		Action:     f.Scope + ".unlocked",
+		TargetType: f.Scope,
+		TargetId:   f.Key,
+		IP:         ip,
+		Metadata: map[string]interface{}{
+			"lockedUntil": f.LockedUntil.Time,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) ClearFailures(ctx context.Context, scope, key string) error {
+	ctx, span := r.tracer.Start(ctx, "account.Repository.ClearFailures")
+	defer span.End()
+
+	command := `
+		DELETE FROM login_failures WHERE scope = $1 AND key = $2 AND locked_until IS NULL
+	`
+
+	_, err := r.sql.ExecContext(ctx, command, scope, key)
+
+	return err
+}
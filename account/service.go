@@ -2,20 +2,54 @@ package account
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"time"
 
 	"github.com/rizface/quora/account/value"
+	"github.com/rizface/quora/metrics"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type Service struct {
-	tracer trace.Tracer
-	repo   *Repository
+	tracer   trace.Tracer
+	repo     *Repository
+	notifier *Notifier
+	policies map[string]value.LockoutPolicy
 }
 
-func NewService(repo *Repository, tracer trace.Tracer) *Service {
+func NewService(repo *Repository, notifier *Notifier, tracer trace.Tracer) *Service {
+	var (
+		delayBase = time.Second
+		delayMax  = time.Minute
+		window    = 15 * time.Minute
+	)
+
 	return &Service{
-		repo:   repo,
-		tracer: tracer,
+		repo:     repo,
+		notifier: notifier,
+		tracer:   tracer,
+		// many users may share an IP, it is only locked out when it guesses
+		// far more than one account would
+		policies: map[string]value.LockoutPolicy{
+			value.LoginScopeAccount: value.NewLockoutPolicy(value.LoginScopeAccount, value.LockoutPolicy{
+				DelayAfter:      5,
+				DelayBase:       delayBase,
+				DelayMax:        delayMax,
+				LockoutAfter:    10,
+				LockoutDuration: 15 * time.Minute,
+				Window:          window,
+			}),
+			value.LoginScopeIP: value.NewLockoutPolicy(value.LoginScopeIP, value.LockoutPolicy{
+				DelayAfter:      20,
+				DelayBase:       delayBase,
+				DelayMax:        delayMax,
+				LockoutAfter:    100,
+				LockoutDuration: time.Hour,
+				Window:          window,
+			}),
+		},
 	}
 }
 
@@ -32,24 +66,133 @@ func (s *Service) Register(ctx context.Context, payload value.AccountPayload) (v
 		return account, err
 	}
 
+	metrics.Registrations.Inc()
+
 	return account, nil
 }
-func (s *Service) Login(ctx context.Context, payload value.AccountPayload) (value.Authenticated, error) {
+
+// Login checks the failed logins of the IP before the account is looked up and
+// the failed logins of the account before the password is verified, so a
+// blocked client can not keep guessing. A successful login forgets the failures
+// of the account but not those of the IP.
+func (s *Service) Login(ctx context.Context, payload value.AccountPayload, ip string) (value.Authenticated, error) {
 	ctx, span := s.tracer.Start(ctx, "account.Service.Login")
 	defer span.End()
 
+	authenticated, err := s.login(ctx, payload, ip)
+
+	switch {
+	case err == nil:
+		metrics.Logins.WithLabelValues(metrics.LoginSucceeded).Inc()
+	case errors.Is(err, ErrCredential) || errors.Is(err, ErrAccountNotFound):
+		metrics.Logins.WithLabelValues(metrics.LoginFailed).Inc()
+	case errors.Is(err, ErrLoginDelayed) || errors.Is(err, ErrLoginLocked):
+		metrics.Logins.WithLabelValues(metrics.LoginBlocked).Inc()
+	}
+
+	return authenticated, err
+}
+
+func (s *Service) login(ctx context.Context, payload value.AccountPayload, ip string) (value.Authenticated, error) {
+	now := time.Now()
+
+	if err := s.checkBlocked(ctx, value.LoginScopeIP, ip, ip, now); err != nil {
+		return value.Authenticated{}, err
+	}
+
 	account := value.NewAccountEntity(payload)
 
 	account, err := s.repo.FindByEmail(ctx, account)
+	if errors.Is(err, ErrAccountNotFound) {
+		if err := s.recordFailure(ctx, value.LoginScopeIP, ip, ip, now); err != nil {
+			return value.Authenticated{}, err
+		}
+
+		return value.Authenticated{}, ErrAccountNotFound
+	}
+
 	if err != nil {
 		return value.Authenticated{}, err
 	}
 
+	if err := s.checkBlocked(ctx, value.LoginScopeAccount, account.Id, ip, now); err != nil {
+		return value.Authenticated{}, err
+	}
+
 	if !account.VerifyPassword(payload.Password) {
+		if err := s.recordFailure(ctx, value.LoginScopeAccount, account.Id, ip, now); err != nil {
+			return value.Authenticated{}, err
+		}
+
+		if err := s.recordFailure(ctx, value.LoginScopeIP, ip, ip, now); err != nil {
+			return value.Authenticated{}, err
+		}
+
 		return value.Authenticated{}, ErrCredential
 	}
 
+	if err := s.repo.ClearFailures(ctx, value.LoginScopeAccount, account.Id); err != nil {
+		return value.Authenticated{}, err
+	}
+
 	authenticated, err := value.NewAuthenticated(account)
 
 	return authenticated, err
 }
+
+// checkBlocked returns a LoginBlockedError while the scope is delayed or
+// locked, an expired lock is lifted.
+func (s *Service) checkBlocked(ctx context.Context, scope, key, ip string, now time.Time) error {
+	span := trace.SpanFromContext(ctx)
+
+	f, err := s.repo.GetFailure(ctx, scope, key)
+	if err != nil {
+		return err
+	}
+
+	if f.LockExpired(now) {
+		return s.repo.Unlock(ctx, f, now, ip)
+	}
+
+	wait := s.policies[scope].Wait(f, now)
+	if wait == 0 {
+		return nil
+	}
+
+	span.AddEvent("login blocked", trace.WithAttributes(
+		attribute.String("scope", scope),
+		attribute.Int("failures", f.Failures),
+		attribute.Bool("locked", f.IsLocked(now)),
+	))
+
+	return LoginBlockedError{
+		Scope:      scope,
+		Locked:     f.IsLocked(now),
+		RetryAfter: wait,
+	}
+}
+
+// recordFailure counts the failure and locks the scope out once it reached the
+// threshold of its policy, the owner of an account is emailed.
+func (s *Service) recordFailure(ctx context.Context, scope, key, ip string, now time.Time) error {
+	policy := s.policies[scope]
+
+	f, err := s.repo.RecordFailure(ctx, scope, key, now, policy.Window)
+	if err != nil {
+		return err
+	}
+
+	if !policy.ShouldLock(f) {
+		return nil
+	}
+
+	until := now.Add(policy.LockoutDuration)
+
+	return s.repo.Lock(ctx, f, until, ip, func(tx *sql.Tx) error {
+		if scope != value.LoginScopeAccount {
+			return nil
+		}
+
+		return s.notifier.Locked(ctx, tx, key, until)
+	})
+}
@@ -0,0 +1,119 @@
+package value
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	LoginScopeAccount = "account"
+	LoginScopeIP      = "ip"
+)
+
+// LoginFailure counts the failed logins of an account or of an IP, Key is the
+// id of the account or the IP.
+type LoginFailure struct {
+	Scope        string
+	Key          string
+	Failures     int
+	LastFailedAt time.Time
+	LockedUntil  sql.NullTime
+}
+
+func (f LoginFailure) IsLocked(now time.Time) bool {
+	return f.LockedUntil.Valid && now.Before(f.LockedUntil.Time)
+}
+
+// LockExpired is true once a lock is over but was not lifted yet
+func (f LoginFailure) LockExpired(now time.Time) bool {
+	return f.LockedUntil.Valid && !now.Before(f.LockedUntil.Time)
+}
+
+// LockoutPolicy slows the logins of a scope down after DelayAfter failures,
+// every failure doubles the delay from DelayBase up to DelayMax, and locks the
+// scope out for LockoutDuration after LockoutAfter failures. Failures older
+// than Window are forgotten.
+type LockoutPolicy struct {
+	DelayAfter      int
+	DelayBase       time.Duration
+	DelayMax        time.Duration
+	LockoutAfter    int
+	LockoutDuration time.Duration
+	Window          time.Duration
+}
+
+// NewLockoutPolicy reads the thresholds of the scope from
+// LOGIN_<SCOPE>_DELAY_AFTER, LOGIN_<SCOPE>_LOCKOUT_AFTER and
+// LOGIN_<SCOPE>_LOCKOUT_DURATION, the delays and the window are shared by the
+// scopes and read from LOGIN_DELAY_BASE, LOGIN_DELAY_MAX and LOGIN_FAILURE_WINDOW.
+func NewLockoutPolicy(scope string, fallback LockoutPolicy) LockoutPolicy {
+	prefix := fmt.Sprintf("LOGIN_%s_", strings.ToUpper(scope))
+
+	return LockoutPolicy{
+		DelayAfter:      envInt(prefix+"DELAY_AFTER", fallback.DelayAfter),
+		DelayBase:       envDuration("LOGIN_DELAY_BASE", fallback.DelayBase),
+		DelayMax:        envDuration("LOGIN_DELAY_MAX", fallback.DelayMax),
+		LockoutAfter:    envInt(prefix+"LOCKOUT_AFTER", fallback.LockoutAfter),
+		LockoutDuration: envDuration(prefix+"LOCKOUT_DURATION", fallback.LockoutDuration),
+		Window:          envDuration("LOGIN_FAILURE_WINDOW", fallback.Window),
+	}
+}
+
+func envInt(key string, fallback int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || v < 1 {
+		return fallback
+	}
+
+	return v
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || v <= 0 {
+		return fallback
+	}
+
+	return v
+}
+
+// Wait is how long the scope has to wait before its next login, zero when it
+// may log in now.
+func (p LockoutPolicy) Wait(f LoginFailure, now time.Time) time.Duration {
+	if f.IsLocked(now) {
+		return f.LockedUntil.Time.Sub(now)
+	}
+
+	if f.Failures < p.DelayAfter || now.Sub(f.LastFailedAt) >= p.Window {
+		return 0
+	}
+
+	delay := p.DelayBase
+	for i := p.DelayAfter; i < f.Failures && delay < p.DelayMax; i++ {
+		delay *= 2
+	}
+
+	if delay > p.DelayMax {
+		delay = p.DelayMax
+	}
+
+	if wait := f.LastFailedAt.Add(delay).Sub(now); wait > 0 {
+		return wait
+	}
+
+	return 0
+}
+
+func (p LockoutPolicy) ShouldLock(f LoginFailure) bool {
+	return f.Failures >= p.LockoutAfter
+}
+
+// LockedNotice is the email sent to the owner of a locked account
+type LockedNotice struct {
+	Account     AccountEntity
+	LockedUntil time.Time
+}
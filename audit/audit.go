@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+const (
+	TargetAccount = "account"
+	TargetIP      = "ip"
+)
+
+// Entry is one line of the audit log. ActorId and IP are left empty when the
+// action was taken by the system.
+type Entry struct {
+	Action     string
+	ActorId    string
+	TargetType string
+	TargetId   string
+	IP         string
+	Metadata   map[string]interface{}
+}
+
+func nullable(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// Record writes the entry with the transaction of the change it describes, so
+// the log never misses a change nor records one that was rolled back.
+func Record(ctx context.Context, tx *sql.Tx, e Entry) error {
+	if e.Metadata == nil {
+		e.Metadata = map[string]interface{}{}
+	}
+
+	metadata, err := json.Marshal(e.Metadata)
+	if err != nil {
+		return err
+	}
+
+	command := `
+		INSERT INTO audit_logs (action, actor_id, target_type, target_id, ip, metadata) VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err = tx.ExecContext(ctx, command, e.Action, nullable(e.ActorId), e.TargetType, e.TargetId, nullable(e.IP), metadata)
+
+	return err
+}
@@ -0,0 +1,219 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rizface/quora/detach"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+)
+
+var ErrMiss = errors.New("cache miss")
+
+// Store keeps raw values by key. Get returns ErrMiss when the key is not
+// stored or has expired, GetMany leaves those keys out of its result. A ttl of
+// zero keeps the value until it is deleted.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	GetMany(ctx context.Context, keys []string) (map[string][]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Cache reads through a Store. Concurrent misses of the same key are merged so
+// only one of them loads the value, the others wait for its result.
+type Cache struct {
+	store       Store
+	tracer      trace.Tracer
+	group       singleflight.Group
+	loadTimeout time.Duration
+}
+
+// New gives a merged load CACHE_LOAD_TIMEOUT to finish, 10s by default.
+func New(store Store, tracer trace.Tracer) *Cache {
+	loadTimeout, err := time.ParseDuration(os.Getenv("CACHE_LOAD_TIMEOUT"))
+	if err != nil || loadTimeout <= 0 {
+		loadTimeout = 10 * time.Second
+	}
+
+	return &Cache{
+		store:       store,
+		tracer:      tracer,
+		loadTimeout: loadTimeout,
+	}
+}
+
+// Fetch returns the value cached under key, or stores the value returned by
+// load for ttl. The cache is only an optimization: a failing store is recorded
+// on the span and the value is loaded as if it was missing.
+func Fetch[T any](ctx context.Context, c *Cache, key string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	ctx, span := c.tracer.Start(ctx, "cache.Cache.Fetch")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("key", key))
+
+	var value T
+
+	data, err := c.store.Get(ctx, key)
+	if err == nil {
+		if err := json.Unmarshal(data, &value); err == nil {
+			span.SetAttributes(attribute.Bool("hit", true))
+			return value, nil
+		}
+	}
+
+	if err != nil && !errors.Is(err, ErrMiss) {
+		span.RecordError(err)
+	}
+
+	span.SetAttributes(attribute.Bool("hit", false))
+
+	result, err, shared := c.group.Do(key, func() (interface{}, error) {
+		// the waiters share the load, it is not cancelled when the caller
+		// that started it goes away
+		ctx, cancel := detach.WithTimeout(ctx, c.loadTimeout)
+		defer cancel()
+
+		value, err := load(ctx)
+		if err != nil {
+			return value, err
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return value, err
+		}
+
+		if err := c.store.Set(ctx, key, data, ttl); err != nil {
+			span.RecordError(err)
+		}
+
+		return value, nil
+	})
+
+	span.SetAttributes(attribute.Bool("shared", shared))
+
+	if err != nil {
+		return value, err
+	}
+
+	return result.(T), nil
+}
+
+// FetchMany is the batched Fetch: the values of the ids are read with a single
+// round trip and the missing ones are loaded with a single call of load, which
+// leaves out the ids that have no value. Concurrent misses are not merged.
+func FetchMany[T any](ctx context.Context, c *Cache, ids []string, key func(id string) string, ttl time.Duration, load func(ctx context.Context, ids []string) (map[string]T, error)) (map[string]T, error) {
+	ctx, span := c.tracer.Start(ctx, "cache.Cache.FetchMany")
+	defer span.End()
+
+	var (
+		values  = make(map[string]T, len(ids))
+		missing = make([]string, 0, len(ids))
+		keys    = make([]string, 0, len(ids))
+	)
+
+	for _, id := range ids {
+		keys = append(keys, key(id))
+	}
+
+	cached, err := c.store.GetMany(ctx, keys)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	for i, id := range ids {
+		var value T
+
+		data, ok := cached[keys[i]]
+		if ok && json.Unmarshal(data, &value) == nil {
+			values[id] = value
+			continue
+		}
+
+		missing = append(missing, id)
+	}
+
+	span.SetAttributes(attribute.Int("hits", len(values)), attribute.Int("misses", len(missing)))
+
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	loaded, err := load(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, value := range loaded {
+		values[id] = value
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.store.Set(ctx, key(id), data, ttl); err != nil {
+			span.RecordError(err)
+		}
+	}
+
+	return values, nil
+}
+
+// Invalidate deletes the keys so the next Fetch loads them again.
+func (c *Cache) Invalidate(ctx context.Context, keys ...string) error {
+	ctx, span := c.tracer.Start(ctx, "cache.Cache.Invalidate")
+	defer span.End()
+
+	return c.store.Delete(ctx, keys...)
+}
+
+// Version returns the current version of a namespace. Keys built with it are
+// all invalidated at once by Bump, which is how the keys of a namespace that
+// cannot be listed, such as every page of a list, are invalidated.
+func (c *Cache) Version(ctx context.Context, namespace string) string {
+	ctx, span := c.tracer.Start(ctx, "cache.Cache.Version")
+	defer span.End()
+
+	data, err := c.store.Get(ctx, versionKey(namespace))
+	if err == nil {
+		return string(data)
+	}
+
+	if !errors.Is(err, ErrMiss) {
+		span.RecordError(err)
+	}
+
+	return "0"
+}
+
+func (c *Cache) Bump(ctx context.Context, namespace string) error {
+	ctx, span := c.tracer.Start(ctx, "cache.Cache.Bump")
+	defer span.End()
+
+	return c.store.Set(ctx, versionKey(namespace), []byte(uuid.NewString()), 0)
+}
+
+func versionKey(namespace string) string {
+	return fmt.Sprintf("%s:version", namespace)
+}
+
+// TTL reads how long the values of name are cached from CACHE_TTL_<NAME>, for
+// example CACHE_TTL_QUESTION_LIST=30s, and falls back to the given duration.
+func TTL(name string, fallback time.Duration) time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("CACHE_TTL_" + strings.ToUpper(name)))
+	if err != nil || ttl <= 0 {
+		return fallback
+	}
+
+	return ttl
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func (e entry) isExpired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// MemoryStore keeps the values in the process, it is meant for a single
+// instance and for running without Redis. Expired values are removed when
+// they are read, and swept at most once a minute when values are set so the
+// keys that are never read again do not pile up.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]entry
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: map[string]entry{},
+	}
+}
+
+func (m *MemoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+
+	if e.isExpired(time.Now()) {
+		delete(m.entries, key)
+		return nil, ErrMiss
+	}
+
+	return e.value, nil
+}
+
+func (m *MemoryStore) GetMany(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+
+	for _, key := range keys {
+		value, err := m.Get(ctx, key)
+		if err == nil {
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+func (m *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(m.lastSweep) >= time.Minute {
+		for key, e := range m.entries {
+			if e.isExpired(now) {
+				delete(m.entries, key)
+			}
+		}
+
+		m.lastSweep = now
+	}
+
+	e := entry{value: value}
+	if ttl > 0 {
+		e.expiresAt = now.Add(ttl)
+	}
+
+	m.entries[key] = e
+
+	return nil
+}
+
+func (m *MemoryStore) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+
+	return nil
+}
@@ -16,9 +16,23 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/redis/go-redis/v9"
 	"github.com/rizface/quora/account"
+	"github.com/rizface/quora/cache"
+	"github.com/rizface/quora/digest"
+	"github.com/rizface/quora/events"
+	"github.com/rizface/quora/feed"
+	"github.com/rizface/quora/health"
+	"github.com/rizface/quora/idempotency"
+	"github.com/rizface/quora/jobs"
+	"github.com/rizface/quora/mailer"
+	"github.com/rizface/quora/metrics"
+	"github.com/rizface/quora/notification"
 	"github.com/rizface/quora/provider"
 	"github.com/rizface/quora/question"
+	"github.com/rizface/quora/ratelimit"
+	"github.com/rizface/quora/realtime"
+	"github.com/rizface/quora/webhook"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -60,88 +74,275 @@ func main() {
 		}
 	}()
 
-	runMigrations(dependencies.sql)
+	version := runMigrations(dependencies.sql)
+	dependencies.health.Add("migrations", health.Migrations(dependencies.sql, version))
 
-	// start the app
-	log.Fatal(app.Start())
+	// start the app, the server is shut down first when the app stops so the
+	// process waits for Stop to exit
+	if err := app.Start(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+
+	select {}
 }
 
 type App struct {
-	Deps     *Dependencies
-	Account  *account.Feature
-	Question *question.Feature
+	Deps         *Dependencies
+	Account      *account.Feature
+	Question     *question.Feature
+	Feed         *feed.Feature
+	Notification *notification.Feature
+	Realtime     *realtime.Feature
+	Webhook      *webhook.Feature
+	Jobs         *jobs.Feature
+	Events       *events.Feature
+	Digest       *digest.Feature
 }
 
 func NewApp(d *Dependencies) *App {
 	return &App{
-		Deps:     d,
-		Account:  account.NewFeature(d.router, d.sql, d.tracer),
-		Question: question.NewFeature(d.router, d.sql, d.tracer),
+		Deps:         d,
+		Account:      account.NewFeature(d.router, d.sql, d.tracer, d.limiter, d.jobs, d.mailer),
+		Question:     question.NewFeature(d.router, d.sql, d.cache, d.tracer, d.limiter, d.idempotency),
+		Feed:         feed.NewFeature(d.router, d.sql, d.tracer),
+		Notification: notification.NewFeature(d.router, d.sql, d.tracer, d.bus),
+		Realtime:     realtime.NewFeature(d.router, d.sql, provider.PostgresDSN(), d.tracer, d.bus),
+		Webhook:      webhook.NewFeature(d.router, d.sql, d.tracer, d.bus),
+		Jobs:         jobs.NewFeature(d.router, d.sql, d.tracer),
+		Events:       events.NewFeature(d.router, d.sql, d.tracer),
+		Digest:       digest.NewFeature(d.router, d.sql, d.tracer, d.jobs, d.mailer),
 	}
 }
 
 func (a *App) Start() error {
 	a.Account.RegisterRoutes()
 	a.Question.RegisterRoutes()
+	a.Feed.RegisterRoutes()
+	a.Notification.RegisterRoutes()
+	a.Realtime.RegisterRoutes()
+	a.Webhook.RegisterRoutes()
+	a.Jobs.RegisterRoutes()
+	a.Events.RegisterRoutes()
+	a.Digest.RegisterRoutes()
+	a.registerAdminRoutes()
+
+	a.Deps.router.Get("/livez", a.Deps.health.Live)
+	a.Deps.router.Get("/readyz", a.Deps.health.Ready)
+
+	// every subscriber is registered by now
+	a.Deps.relay.Start()
+	a.Question.Worker.Start()
+	a.Feed.Worker.Start()
+	a.Realtime.Hub.Start()
+	a.Webhook.Worker.Start()
+	a.Deps.jobs.Start()
+
+	if a.Deps.adminServer != nil {
+		go func() {
+			if err := a.Deps.adminServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("failed to serve the admin endpoints: %v", err)
+			}
+		}()
+	}
 
 	err := a.Deps.server.ListenAndServe()
 
 	return err
 }
 
+// registerAdminRoutes serves the admin endpoints on the admin server when there
+// is one, or next to the routes of the app.
+func (a *App) registerAdminRoutes() {
+	r := a.Deps.router
+	if a.Deps.adminServer != nil {
+		r = a.Deps.adminRouter
+	}
+
+	r.Method(http.MethodGet, "/metrics", metrics.Handler())
+}
+
 func (s *App) Stop(ctx context.Context) error {
-	err := s.Deps.sql.Close()
+	// the instance is taken out of the load balancer before the server stops
+	// taking requests, then the requests in flight are drained before their
+	// dependencies are stopped
+	s.Deps.health.Drain()
+	log.Println("readiness is failing")
+
+	select {
+	case <-time.After(s.Deps.drainDelay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// the streams and the websockets never return by themselves, they are
+	// closed first so the server does not wait for them until the deadline
+	err := s.Realtime.Gateway.Stop(ctx)
 	if err != nil {
 		return err
 	}
-	log.Println("SQL connection closed")
+	log.Println("websocket gateway stopped")
 
-	err = s.Deps.traceProvider.Shutdown(ctx)
+	err = s.Realtime.Hub.Stop(ctx)
 	if err != nil {
 		return err
 	}
-	log.Println("OTel TraceProvider shutdown")
+	log.Println("realtime hub stopped")
 
 	err = s.Deps.server.Shutdown(ctx)
 	if err != nil {
 		return err
 	}
+	log.Println("server drained")
+
+	err = s.Deps.relay.Stop(ctx)
+	if err != nil {
+		return err
+	}
+	log.Println("events relay stopped")
+
+	err = s.Question.Worker.Stop(ctx)
+	if err != nil {
+		return err
+	}
+	log.Println("hot score worker stopped")
+
+	err = s.Feed.Worker.Stop(ctx)
+	if err != nil {
+		return err
+	}
+	log.Println("feed worker stopped")
+
+	err = s.Webhook.Worker.Stop(ctx)
+	if err != nil {
+		return err
+	}
+	log.Println("webhook worker stopped")
+
+	// drains the running jobs before their connections are closed
+	err = s.Deps.jobs.Stop(ctx)
+	if err != nil {
+		return err
+	}
+	log.Println("job runner stopped")
+
+	err = s.Deps.sql.Close()
+	if err != nil {
+		return err
+	}
+	log.Println("SQL connection closed")
+
+	if s.Deps.redis != nil {
+		err = s.Deps.redis.Close()
+		if err != nil {
+			return err
+		}
+		log.Println("Redis connection closed")
+	}
+
+	err = s.Deps.traceProvider.Shutdown(ctx)
+	if err != nil {
+		return err
+	}
+	log.Println("OTel TraceProvider shutdown")
+
+	if s.Deps.adminServer != nil {
+		err = s.Deps.adminServer.Shutdown(ctx)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
 type Dependencies struct {
 	server        *http.Server
+	adminServer   *http.Server
+	adminRouter   *chi.Mux
 	router        *chi.Mux
 	sql           *sql.DB
 	tracer        trace.Tracer
 	traceProvider *sdktrace.TracerProvider
+	bus           *events.Bus
+	relay         *events.Relay
+	jobs          *jobs.Runner
+	mailer        mailer.Sender
+	redis         *redis.Client
+	cache         *cache.Cache
+	limiter       *ratelimit.Limiter
+	idempotency   *idempotency.Guard
+	health        *health.Checker
+	drainDelay    time.Duration
 }
 
 func InitDependencies() *Dependencies {
 	router := provider.ProvideRouter()
 	server := provider.ProviderServer(router)
+	adminRouter := chi.NewRouter()
+	adminServer := provider.ProvideAdminServer(adminRouter)
 
-	sql, err := provider.ProvideSQL()
+	traceProvider, tracer, exporterCheck, err := provider.ProvideOtel()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	traceProvider, tracer, err := provider.ProvideOtel()
+	sql, err := provider.ProvideSQL(tracer)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	metrics.RegisterDB(sql, "quora")
+
+	rdb, err := provider.ProvideRedis()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var (
+		eventsRepo = events.NewRepository(sql, tracer)
+		bus        = events.NewBus(eventsRepo, tracer)
+		checker    = health.NewChecker()
+	)
+
+	checker.Add("db", sql.PingContext)
+	checker.Add("trace_exporter", exporterCheck)
+
+	// the cache is in the memory of the process without Redis
+	if rdb != nil {
+		checker.Add("cache", func(ctx context.Context) error {
+			return rdb.Ping(ctx).Err()
+		})
+	}
+
+	// long enough for the probes to see the failing readiness
+	drainDelay, err := time.ParseDuration(os.Getenv("SHUTDOWN_DRAIN_DELAY"))
+	if err != nil || drainDelay < 0 {
+		drainDelay = 5 * time.Second
+	}
+
 	return &Dependencies{
 		router:        router,
 		server:        server,
+		adminServer:   adminServer,
+		adminRouter:   adminRouter,
 		sql:           sql,
 		tracer:        tracer,
 		traceProvider: traceProvider,
+		bus:           bus,
+		relay:         events.NewRelay(sql, provider.PostgresDSN(), eventsRepo, bus, tracer),
+		jobs:          jobs.NewRunner(jobs.NewRepository(sql, tracer), tracer),
+		mailer:        provider.ProvideMailer(),
+		redis:         rdb,
+		cache:         cache.New(provider.ProvideCacheStore(rdb), tracer),
+		limiter:       ratelimit.NewLimiter(provider.ProvideRateLimitStore(rdb), tracer),
+		idempotency:   idempotency.NewGuard(provider.ProvideIdempotencyStore(rdb), tracer),
+		health:        checker,
+		drainDelay:    drainDelay,
 	}
 }
 
-func runMigrations(sql *sql.DB) {
+// runMigrations returns the version of the schema the app runs with
+func runMigrations(sql *sql.DB) uint {
 	driver, err := postgres.WithInstance(sql, &postgres.Config{})
 	if err != nil {
 		log.Fatalf("failed create pg instance: %v", err)
@@ -157,5 +358,12 @@ func runMigrations(sql *sql.DB) {
 		log.Fatalf("failed run migrations: %v", err)
 	}
 
+	version, _, err := m.Version()
+	if err != nil {
+		log.Fatalf("failed get migration version: %v", err)
+	}
+
 	log.Print("success run migrations")
+
+	return version
 }
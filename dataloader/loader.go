@@ -0,0 +1,87 @@
+package dataloader
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchFunc loads the values of many keys at once, typically with a single
+// query filtered by `= ANY($1)`. Keys that have no value are left out of the
+// returned map.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader collects the keys needed to build a result and loads them in a
+// single batch instead of one query per row. The values are kept for the
+// life of the loader, so a loader is meant to be used for one request or one
+// list and then dropped.
+type Loader[K comparable, V any] struct {
+	fetch  BatchFunc[K, V]
+	mu     sync.Mutex
+	loaded map[K]V
+}
+
+func New[K comparable, V any](fetch BatchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:  fetch,
+		loaded: map[K]V{},
+	}
+}
+
+// LoadMany returns the values of the keys. Duplicated keys and keys loaded
+// before are not fetched again, the rest are fetched in a single batch. Keys
+// without a value are missing from the result.
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) (map[K]V, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var (
+		missing = make([]K, 0, len(keys))
+		seen    = make(map[K]struct{}, len(keys))
+	)
+
+	for _, key := range keys {
+		if _, ok := l.loaded[key]; ok {
+			continue
+		}
+
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		missing = append(missing, key)
+	}
+
+	if len(missing) > 0 {
+		fetched, err := l.fetch(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, value := range fetched {
+			l.loaded[key] = value
+		}
+	}
+
+	values := make(map[K]V, len(keys))
+	for _, key := range keys {
+		if value, ok := l.loaded[key]; ok {
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+// Load returns the value of a single key and whether it exists.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, bool, error) {
+	values, err := l.LoadMany(ctx, []K{key})
+	if err != nil {
+		var zero V
+		return zero, false, err
+	}
+
+	value, ok := values[key]
+
+	return value, ok, nil
+}
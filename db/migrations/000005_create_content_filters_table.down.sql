@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS moderation_queue;
+DROP TABLE IF EXISTS space_content_filters;
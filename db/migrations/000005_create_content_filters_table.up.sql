@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS space_content_filters(
+    space_id UUID NOT NULL PRIMARY KEY REFERENCES spaces(id) ON DELETE CASCADE,
+    banned_words TEXT[] NOT NULL DEFAULT '{}',
+    max_links INT NOT NULL DEFAULT 3,
+    max_uppercase_ratio REAL NOT NULL DEFAULT 0.7,
+    max_repeated_chars INT NOT NULL DEFAULT 6,
+    duplicate_window INT NOT NULL DEFAULT 5,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS moderation_queue(
+    id UUID NOT NULL PRIMARY KEY,
+    content_type VARCHAR(10) NOT NULL,
+    content_id UUID NOT NULL,
+    author_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    space_id UUID REFERENCES spaces(id) ON DELETE CASCADE DEFAULT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1 @@
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS role VARCHAR(10) NOT NULL DEFAULT 'member';
@@ -0,0 +1,2 @@
+ALTER TABLE questions DROP COLUMN IF EXISTS duplicate_of;
+DROP INDEX IF EXISTS questions_question_trgm_idx;
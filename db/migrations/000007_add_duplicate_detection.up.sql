@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS questions_question_trgm_idx ON questions USING GIN (question gin_trgm_ops);
+
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS duplicate_of UUID REFERENCES questions(id) ON DELETE SET NULL DEFAULT NULL;
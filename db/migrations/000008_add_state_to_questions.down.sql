@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS question_reopen_votes;
+ALTER TABLE questions DROP COLUMN IF EXISTS state, DROP COLUMN IF EXISTS state_reason;
@@ -0,0 +1,12 @@
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS state VARCHAR(10) NOT NULL DEFAULT 'open',
+    ADD COLUMN IF NOT EXISTS state_reason TEXT DEFAULT NULL;
+
+UPDATE questions SET state = 'closed', state_reason = 'duplicate' WHERE duplicate_of IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS question_reopen_votes(
+    question_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    voter_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(question_id, voter_id)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS related_questions;
+DROP INDEX IF EXISTS questions_tags_idx;
+ALTER TABLE questions DROP COLUMN IF EXISTS tags;
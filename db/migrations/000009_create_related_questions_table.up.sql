@@ -0,0 +1,13 @@
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS questions_tags_idx ON questions USING GIN (tags);
+
+CREATE TABLE IF NOT EXISTS related_questions(
+    question_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    related_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(question_id, related_id)
+);
+
+CREATE INDEX IF NOT EXISTS related_questions_related_id_idx ON related_questions(related_id);
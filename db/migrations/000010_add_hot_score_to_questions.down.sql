@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS questions_created_at_idx;
+DROP INDEX IF EXISTS questions_hot_score_idx;
+ALTER TABLE questions DROP COLUMN IF EXISTS hot_score, DROP COLUMN IF EXISTS hot_score_updated_at;
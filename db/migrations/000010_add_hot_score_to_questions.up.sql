@@ -0,0 +1,6 @@
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS hot_score DOUBLE PRECISION DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS hot_score_updated_at TIMESTAMP DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS questions_hot_score_idx ON questions(hot_score DESC NULLS LAST);
+CREATE INDEX IF NOT EXISTS questions_created_at_idx ON questions(created_at DESC);
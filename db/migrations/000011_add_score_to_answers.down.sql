@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS answers_question_id_score_idx;
+ALTER TABLE answers DROP COLUMN IF EXISTS score;
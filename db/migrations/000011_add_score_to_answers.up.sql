@@ -0,0 +1,13 @@
+ALTER TABLE answers ADD COLUMN IF NOT EXISTS score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+-- wilson score lower bound with 95% confidence (z = 1.96)
+UPDATE answers SET score = (
+    (upvote::float8 / (upvote + downvote)) + 1.96 * 1.96 / (2 * (upvote + downvote))
+    - 1.96 * SQRT(
+        ((upvote::float8 / (upvote + downvote)) * (1 - upvote::float8 / (upvote + downvote))
+        + 1.96 * 1.96 / (4 * (upvote + downvote))) / (upvote + downvote)
+    )
+) / (1 + 1.96 * 1.96 / (upvote + downvote))
+WHERE upvote + downvote > 0;
+
+CREATE INDEX IF NOT EXISTS answers_question_id_score_idx ON answers(question_id, score DESC);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS feed_cursor;
+DROP TABLE IF EXISTS feed_seen;
+DROP TABLE IF EXISTS feed_items;
+DROP TABLE IF EXISTS follows;
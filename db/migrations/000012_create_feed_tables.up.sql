@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS follows(
+    follower_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    target_type VARCHAR(10) NOT NULL,
+    target_id VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(follower_id, target_type, target_id)
+);
+
+CREATE INDEX IF NOT EXISTS follows_target_idx ON follows(target_type, target_id);
+
+CREATE TABLE IF NOT EXISTS feed_items(
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    question_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    reason VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(account_id, question_id)
+);
+
+CREATE TABLE IF NOT EXISTS feed_seen(
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    question_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(account_id, question_id)
+);
+
+-- position of the fan out worker in the questions table
+CREATE TABLE IF NOT EXISTS feed_cursor(
+    id VARCHAR(10) NOT NULL PRIMARY KEY,
+    last_created_at TIMESTAMP NOT NULL,
+    last_question_id UUID NOT NULL
+);
+
+INSERT INTO feed_cursor(id, last_created_at, last_question_id)
+VALUES ('fanout', CURRENT_TIMESTAMP, '00000000-0000-0000-0000-000000000000')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS question_followers(
+    question_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(question_id, account_id)
+);
+
+CREATE INDEX IF NOT EXISTS question_followers_account_id_idx ON question_followers(account_id, created_at DESC);
+
+-- askers follow their own questions
+INSERT INTO question_followers(question_id, account_id)
+SELECT id, author_id FROM questions
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS notifications(
+    id UUID NOT NULL PRIMARY KEY,
+    recipient_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    "type" VARCHAR(30) NOT NULL,
+    actor_id UUID REFERENCES accounts(id) ON DELETE CASCADE DEFAULT NULL,
+    question_id UUID REFERENCES questions(id) ON DELETE CASCADE DEFAULT NULL,
+    answer_id UUID REFERENCES answers(id) ON DELETE CASCADE DEFAULT NULL,
+    read_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS notifications_recipient_id_idx ON notifications(recipient_id, created_at DESC);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS notification_preferences;
+DROP INDEX IF EXISTS notifications_unread_idx;
+ALTER TABLE notifications DROP COLUMN IF EXISTS data;
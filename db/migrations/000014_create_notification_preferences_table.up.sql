@@ -0,0 +1,11 @@
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS data JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS notifications_unread_idx ON notifications(recipient_id) WHERE read_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS notification_preferences(
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    "type" VARCHAR(30) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(account_id, "type")
+);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS notifications_notify ON notifications;
+DROP FUNCTION IF EXISTS notify_notification_created;
+DROP TABLE IF EXISTS question_presence;
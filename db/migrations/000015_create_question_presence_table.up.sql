@@ -0,0 +1,37 @@
+-- presence is short lived and rebuilt by connected clients, it does not need
+-- to survive a crash
+CREATE UNLOGGED TABLE IF NOT EXISTS question_presence(
+    connection_id UUID NOT NULL,
+    question_id UUID NOT NULL,
+    account_id UUID NOT NULL,
+    activity VARCHAR(10) NOT NULL,
+    seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(connection_id, question_id)
+);
+
+CREATE INDEX IF NOT EXISTS question_presence_question_id_idx ON question_presence(question_id);
+
+CREATE OR REPLACE FUNCTION notify_notification_created() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('account_notifications', json_build_object(
+        'type', 'notification',
+        'accountId', NEW.recipient_id,
+        'data', json_build_object(
+            'id', NEW.id,
+            'recipientId', NEW.recipient_id,
+            'type', NEW."type",
+            'actorId', NEW.actor_id,
+            'questionId', NEW.question_id,
+            'answerId', NEW.answer_id,
+            'data', NEW.data,
+            'readAt', NEW.read_at,
+            'createdAt', NEW.created_at
+        )
+    )::text);
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notifications_notify AFTER INSERT ON notifications
+FOR EACH ROW EXECUTE FUNCTION notify_notification_created();
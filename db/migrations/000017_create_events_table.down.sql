@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS events_recorded ON events;
+DROP FUNCTION IF EXISTS notify_events_recorded;
+DROP TABLE IF EXISTS processed_events;
+DROP TABLE IF EXISTS events;
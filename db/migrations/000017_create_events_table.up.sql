@@ -0,0 +1,35 @@
+-- outbox of domain events, written in the transaction of the change and
+-- relayed to the subscribers in the background
+CREATE TABLE IF NOT EXISTS events (
+    id UUID PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT now(),
+    published_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS events_unpublished_idx ON events (next_attempt_at, created_at) WHERE published_at IS NULL;
+
+-- events a consumer already handled, an event is delivered at least once so
+-- consumers skip the events they have seen
+CREATE TABLE IF NOT EXISTS processed_events (
+    consumer VARCHAR(100) NOT NULL,
+    event_id UUID NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (consumer, event_id)
+);
+
+-- wakes up the relay once the transaction that wrote the events commits
+CREATE OR REPLACE FUNCTION notify_events_recorded() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('events_recorded', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER events_recorded
+AFTER INSERT ON events
+FOR EACH STATEMENT EXECUTE FUNCTION notify_events_recorded();
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    queue VARCHAR(50) NOT NULL,
+    kind VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    -- pending, running, succeeded or failed
+    status VARCHAR(10) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    run_at TIMESTAMP NOT NULL DEFAULT now(),
+    -- a running job whose lease expired was abandoned by a crashed runner
+    locked_until TIMESTAMP,
+    -- enqueueing a job with the key of an existing job is a no-op, the
+    -- schedules use it so every instance enqueues a run only once
+    unique_key VARCHAR(200),
+    last_error TEXT,
+    finished_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS jobs_due_idx ON jobs (queue, run_at) WHERE status IN ('pending', 'running');
+CREATE INDEX IF NOT EXISTS jobs_status_idx ON jobs (status, created_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS jobs_unique_key_idx ON jobs (unique_key) WHERE unique_key IS NOT NULL;
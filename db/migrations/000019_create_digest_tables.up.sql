@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS digest_subscriptions(
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    -- daily or weekly
+    frequency VARCHAR(10) NOT NULL,
+    -- lets the recipient unsubscribe from the email without logging in
+    unsubscribe_token VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- one row per digest sent, the primary key keeps a digest from being sent twice
+CREATE TABLE IF NOT EXISTS digest_deliveries(
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    frequency VARCHAR(10) NOT NULL,
+    period_start TIMESTAMP NOT NULL,
+    questions INT NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(account_id, frequency, period_start)
+);
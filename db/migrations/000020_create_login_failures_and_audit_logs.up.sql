@@ -0,0 +1,24 @@
+-- failed logins of an account or of an ip, counted again once the window passed
+CREATE TABLE IF NOT EXISTS login_failures(
+    -- account or ip
+    scope VARCHAR(10) NOT NULL,
+    key VARCHAR(64) NOT NULL,
+    failures INT NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP DEFAULT NULL,
+    PRIMARY KEY(scope, key)
+);
+
+CREATE TABLE IF NOT EXISTS audit_logs(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    action VARCHAR(50) NOT NULL,
+    -- NULL when the action was taken by the system
+    actor_id UUID REFERENCES accounts(id) ON DELETE SET NULL DEFAULT NULL,
+    target_type VARCHAR(20) NOT NULL,
+    target_id VARCHAR(64) NOT NULL,
+    ip VARCHAR(45) DEFAULT NULL,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS audit_logs_target_idx ON audit_logs(target_type, target_id, created_at DESC);
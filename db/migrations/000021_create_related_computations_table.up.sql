@@ -0,0 +1,10 @@
+-- when the related questions of a question were last computed, it is kept even
+-- when nothing is related so the empty result is cached as well
+CREATE TABLE IF NOT EXISTS related_computations(
+    question_id UUID PRIMARY KEY REFERENCES questions(id) ON DELETE CASCADE,
+    computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO related_computations (question_id, computed_at)
+SELECT question_id, MAX(computed_at) FROM related_questions GROUP BY question_id
+ON CONFLICT DO NOTHING;
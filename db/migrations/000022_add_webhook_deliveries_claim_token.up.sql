@@ -0,0 +1,3 @@
+-- set by every claim, an attempt is only saved by the sender holding the
+-- latest claim of the delivery
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS claim_token UUID DEFAULT NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS events_dead_idx;
+
+DROP INDEX IF EXISTS events_unpublished_idx;
+
+CREATE INDEX IF NOT EXISTS events_unpublished_idx ON events (next_attempt_at, created_at) WHERE published_at IS NULL;
+
+ALTER TABLE events DROP COLUMN IF EXISTS dead_at;
@@ -0,0 +1,9 @@
+-- an event whose attempts ran out is dead, it is kept until a moderator
+-- retries it
+ALTER TABLE events ADD COLUMN IF NOT EXISTS dead_at TIMESTAMP;
+
+DROP INDEX IF EXISTS events_unpublished_idx;
+
+CREATE INDEX IF NOT EXISTS events_unpublished_idx ON events (next_attempt_at, created_at) WHERE published_at IS NULL AND dead_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS events_dead_idx ON events (dead_at) WHERE dead_at IS NOT NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS questions_hot_score_stale_idx;
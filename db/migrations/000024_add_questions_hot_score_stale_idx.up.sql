@@ -0,0 +1,9 @@
+-- a question whose answers or votes changed has no hot_score_updated_at until
+-- the hot score worker recomputes its score
+UPDATE questions q SET hot_score_updated_at = NULL
+WHERE EXISTS (
+    SELECT 1 FROM answers a
+    WHERE a.question_id = q.id AND (a.updated_at > q.hot_score_updated_at OR a.created_at > q.hot_score_updated_at)
+);
+
+CREATE INDEX IF NOT EXISTS questions_hot_score_stale_idx ON questions(created_at) WHERE hot_score_updated_at IS NULL;
@@ -0,0 +1,27 @@
+package detach
+
+import (
+	"context"
+	"time"
+)
+
+// detached keeps the values of its parent, such as the span and the request
+// id, but is never cancelled with it.
+type detached struct {
+	parent context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detached) Done() <-chan struct{} { return nil }
+
+func (detached) Err() error { return nil }
+
+func (d detached) Value(key interface{}) interface{} { return d.parent.Value(key) }
+
+// WithTimeout returns a context that outlives the cancellation of ctx, for the
+// work that must finish once it started even when the client went away. It is
+// bounded by its own timeout instead.
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(detached{parent: ctx}, timeout)
+}
@@ -0,0 +1,46 @@
+package digest
+
+import (
+	"database/sql"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/jobs"
+	"github.com/rizface/quora/mailer"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Feature struct {
+	handler *Handler
+	r       *chi.Mux
+}
+
+func NewFeature(r *chi.Mux, db *sql.DB, tracer trace.Tracer, runner *jobs.Runner, sender mailer.Sender) *Feature {
+	var (
+		repo    = NewRepository(db, tracer)
+		svc     = NewService(repo, tracer)
+		handler = NewHandler(svc, tracer)
+	)
+
+	NewScheduler(repo, runner, sender, tracer).Register()
+
+	return &Feature{
+		handler: handler,
+		r:       r,
+	}
+}
+
+func (f *Feature) RegisterRoutes() {
+	// opened from the emails without a token, the link shows a confirmation
+	// and the confirmation or the mail client sends the POST
+	f.r.Get("/digest/unsubscribe", f.handler.ConfirmUnsubscribe)
+	f.r.Post("/digest/unsubscribe", f.handler.UnsubscribeByToken)
+
+	f.r.Group(func(r chi.Router) {
+		r.Use(identifier.Identifier)
+
+		r.Get("/digest", f.handler.GetSubscription)
+		r.Put("/digest", f.handler.Subscribe)
+		r.Delete("/digest", f.handler.Unsubscribe)
+	})
+}
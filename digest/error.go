@@ -0,0 +1,5 @@
+package digest
+
+import "errors"
+
+var ErrSubscriptionNotFound = errors.New("digest subscription not found")
@@ -0,0 +1,208 @@
+package digest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/rizface/quora/digest/value"
+	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/stdres"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Handler struct {
+	tracer trace.Tracer
+	svc    *Service
+}
+
+func NewHandler(svc *Service, tracer trace.Tracer) *Handler {
+	return &Handler{
+		tracer: tracer,
+		svc:    svc,
+	}
+}
+
+func (h *Handler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "digest.Handler.GetSubscription")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	subscription, err := h.svc.GetSubscription(ctx, Input{
+		Identity: *identity,
+	})
+	if errors.Is(err, ErrSubscriptionNotFound) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusNotFound,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while get digest subscription: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{
+			"doc": subscription,
+		},
+	})
+}
+
+func (h *Handler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "digest.Handler.Subscribe")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	var payload value.SubscriptionPayload
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	subscription, err := h.svc.Subscribe(ctx, Input{
+		Identity:            *identity,
+		SubscriptionPayload: payload,
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while subscribe to digest: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{
+			"doc": subscription,
+		},
+	})
+}
+
+func (h *Handler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "digest.Handler.Unsubscribe")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	err = h.svc.Unsubscribe(ctx, Input{
+		Identity: *identity,
+	})
+	h.writeUnsubscribed(w, r, span, err)
+}
+
+// ConfirmUnsubscribe changes nothing, it renders the form that unsubscribes
+func (h *Handler) ConfirmUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	_, span := h.tracer.Start(r.Context(), "digest.Handler.ConfirmUnsubscribe")
+	defer span.End()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if err := RenderUnsubscribe(w, r.URL.Query().Get("token")); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while render unsubscribe confirmation: %v", err))
+	}
+}
+
+func (h *Handler) UnsubscribeByToken(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "digest.Handler.UnsubscribeByToken")
+	defer span.End()
+
+	err := h.svc.UnsubscribeByToken(ctx, Input{
+		Token: r.URL.Query().Get("token"),
+	})
+	h.writeUnsubscribed(w, r, span, err)
+}
+
+func (h *Handler) writeUnsubscribed(w http.ResponseWriter, r *http.Request, span trace.Span, err error) {
+	if errors.Is(err, ErrSubscriptionNotFound) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusNotFound,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while unsubscribe from digest: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+	})
+}
@@ -0,0 +1,55 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	texttemplate "text/template"
+
+	"github.com/rizface/quora/digest/value"
+	"github.com/rizface/quora/mailer"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var (
+	htmlTemplate        = htmltemplate.Must(htmltemplate.ParseFS(templates, "templates/digest.html"))
+	textTemplate        = texttemplate.Must(texttemplate.ParseFS(templates, "templates/digest.txt"))
+	unsubscribeTemplate = htmltemplate.Must(htmltemplate.ParseFS(templates, "templates/unsubscribe.html"))
+)
+
+// Render builds the email of the digest, the html template escapes the
+// content of the questions.
+func Render(d value.Digest) (mailer.Message, error) {
+	var html, text bytes.Buffer
+
+	if err := htmlTemplate.Execute(&html, d); err != nil {
+		return mailer.Message{}, err
+	}
+
+	if err := textTemplate.Execute(&text, d); err != nil {
+		return mailer.Message{}, err
+	}
+
+	return mailer.Message{
+		To:      d.Recipient.Email,
+		Subject: fmt.Sprintf("Your %s digest: %d new questions", d.Frequency, len(d.Questions)),
+		Text:    text.String(),
+		HTML:    html.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe": fmt.Sprintf("<%s>", d.UnsubscribeUrl),
+			// mail clients unsubscribe with a POST to the url, RFC 8058
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+// RenderUnsubscribe writes the page that confirms the unsubscription, the link
+// of the email only opens it so the scanners following links do not
+// unsubscribe anyone.
+func RenderUnsubscribe(w io.Writer, token string) error {
+	return unsubscribeTemplate.Execute(w, map[string]string{"Token": token})
+}
@@ -0,0 +1,267 @@
+package digest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rizface/quora/digest/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Repository struct {
+	db     *sql.DB
+	tracer trace.Tracer
+}
+
+func NewRepository(db *sql.DB, tracer trace.Tracer) *Repository {
+	return &Repository{
+		db:     db,
+		tracer: tracer,
+	}
+}
+
+func (r *Repository) GetSubscription(ctx context.Context, accountId string) (value.Subscription, error) {
+	ctx, span := r.tracer.Start(ctx, "digest.Repository.GetSubscription")
+	defer span.End()
+
+	var (
+		s     value.Subscription
+		query = `
+			SELECT account_id, frequency, created_at, updated_at FROM digest_subscriptions WHERE account_id = $1
+		`
+	)
+
+	err := r.db.QueryRowContext(ctx, query, accountId).Scan(&s.AccountId, &s.Frequency, &s.CreatedAt, &s.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return value.Subscription{}, ErrSubscriptionNotFound
+	}
+
+	return s, err
+}
+
+// Subscribe keeps the unsubscribe token of an existing subscription, so the
+// links of the digests already sent keep working.
+func (r *Repository) Subscribe(ctx context.Context, s value.Subscription) (value.Subscription, error) {
+	ctx, span := r.tracer.Start(ctx, "digest.Repository.Subscribe")
+	defer span.End()
+
+	command := `
+		INSERT INTO digest_subscriptions (account_id, frequency, unsubscribe_token, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (account_id) DO UPDATE SET frequency = EXCLUDED.frequency, updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`
+
+	err := r.db.
+		QueryRowContext(ctx, command, s.AccountId, s.Frequency, s.UnsubscribeToken, s.CreatedAt, s.UpdatedAt).
+		Scan(&s.CreatedAt)
+
+	return s, err
+}
+
+func (r *Repository) Unsubscribe(ctx context.Context, accountId string) error {
+	ctx, span := r.tracer.Start(ctx, "digest.Repository.Unsubscribe")
+	defer span.End()
+
+	command := `
+		DELETE FROM digest_subscriptions WHERE account_id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, command, accountId)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result)
+}
+
+func (r *Repository) UnsubscribeByToken(ctx context.Context, token string) error {
+	ctx, span := r.tracer.Start(ctx, "digest.Repository.UnsubscribeByToken")
+	defer span.End()
+
+	command := `
+		DELETE FROM digest_subscriptions WHERE unsubscribe_token = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, command, token)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result)
+}
+
+func expectAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// GetDue returns the subscribers of the frequency that have not received the
+// digest of the period yet.
+func (r *Repository) GetDue(ctx context.Context, frequency string, periodStart time.Time) ([]string, error) {
+	ctx, span := r.tracer.Start(ctx, "digest.Repository.GetDue")
+	defer span.End()
+
+	var (
+		accountIds = []string{}
+		query      = `
+			SELECT s.account_id FROM digest_subscriptions s
+			WHERE s.frequency = $1 AND NOT EXISTS (
+				SELECT 1 FROM digest_deliveries d
+				WHERE d.account_id = s.account_id AND d.frequency = s.frequency AND d.period_start = $2
+			)
+		`
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, frequency, periodStart)
+	if err != nil {
+		return accountIds, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var accountId string
+
+		if err := rows.Scan(&accountId); err != nil {
+			return accountIds, err
+		}
+
+		accountIds = append(accountIds, accountId)
+	}
+
+	return accountIds, rows.Err()
+}
+
+func (r *Repository) GetRecipient(ctx context.Context, accountId string) (value.Recipient, error) {
+	ctx, span := r.tracer.Start(ctx, "digest.Repository.GetRecipient")
+	defer span.End()
+
+	var (
+		recipient value.Recipient
+		query     = `
+			SELECT a.id, a.username, a.email, s.frequency, s.unsubscribe_token
+			FROM digest_subscriptions s
+			INNER JOIN accounts a ON a.id = s.account_id
+			WHERE s.account_id = $1
+		`
+	)
+
+	err := r.db.QueryRowContext(ctx, query, accountId).Scan(
+		&recipient.AccountId,
+		&recipient.Username,
+		&recipient.Email,
+		&recipient.Frequency,
+		&recipient.UnsubscribeToken,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return value.Recipient{}, ErrSubscriptionNotFound
+	}
+
+	return recipient, err
+}
+
+// GetTopQuestions returns the hottest questions asked in the period in the
+// tags and spaces the account follows, with their best answer.
+func (r *Repository) GetTopQuestions(ctx context.Context, accountId string, start, end time.Time, limit int) ([]value.Question, error) {
+	ctx, span := r.tracer.Start(ctx, "digest.Repository.GetTopQuestions")
+	defer span.End()
+
+	var (
+		questions = []value.Question{}
+		query     = `
+			SELECT q.id, q.question, q.tags, COALESCE(s.name, ''),
+				(SELECT COUNT(*) FROM answers WHERE question_id = q.id),
+				COALESCE(top.answer, '')
+			FROM questions q
+			LEFT JOIN spaces s ON s.id = q.space_id
+			LEFT JOIN LATERAL (
+				SELECT LEFT(answer, 300) AS answer FROM answers
+				WHERE question_id = q.id
+				ORDER BY score DESC, created_at ASC
+				LIMIT 1
+			) top ON TRUE
+			WHERE q.created_at >= $2 AND q.created_at < $3
+			AND q.author_id <> $1 AND q.duplicate_of IS NULL
+			AND EXISTS (
+				SELECT 1 FROM follows f
+				WHERE f.follower_id = $1 AND (
+					(f.target_type = 'space' AND f.target_id = q.space_id::text)
+					OR (f.target_type = 'tag' AND f.target_id = ANY(q.tags))
+				)
+			)
+			ORDER BY q.hot_score DESC NULLS LAST, q.created_at DESC
+			LIMIT $4
+		`
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, accountId, start, end, limit)
+	if err != nil {
+		return questions, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var q value.Question
+
+		err := rows.Scan(&q.Id, &q.Question, pq.Array(&q.Tags), &q.Space, &q.Answers, &q.TopAnswer)
+		if err != nil {
+			return questions, err
+		}
+
+		questions = append(questions, q)
+	}
+
+	return questions, rows.Err()
+}
+
+// Deliver records the digest of the period and runs send in the same
+// transaction. The row is locked until send returns so concurrent runs of the
+// same digest wait for each other, a failed send is rolled back so it is
+// retried. Only a commit failing after the email left sends it twice. It
+// returns false when the digest was already sent.
+func (r *Repository) Deliver(ctx context.Context, recipient value.Recipient, periodStart time.Time, questions int, send func() error) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "digest.Repository.Deliver")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	command := `
+		INSERT INTO digest_deliveries (account_id, frequency, period_start, questions) VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+	`
+
+	result, err := tx.ExecContext(ctx, command, recipient.AccountId, recipient.Frequency, periodStart, questions)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if affected == 0 {
+		return false, nil
+	}
+
+	if err := send(); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
@@ -0,0 +1,164 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/rizface/quora/digest/value"
+	"github.com/rizface/quora/jobs"
+	jobvalue "github.com/rizface/quora/jobs/value"
+	"github.com/rizface/quora/mailer"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// scheduleJob enqueues one sendJob per subscriber that is due
+	scheduleJob struct{}
+
+	sendJob struct {
+		AccountId   string    `json:"accountId"`
+		Frequency   string    `json:"frequency"`
+		PeriodStart time.Time `json:"periodStart"`
+	}
+)
+
+func (scheduleJob) Kind() string { return "digest.schedule" }
+func (sendJob) Kind() string     { return "digest.send" }
+
+// Scheduler sends the digests through the job runner. Every send is a job of
+// its own keyed by the recipient and the period, so a digest is enqueued once
+// even when several instances schedule it, and a failed email is retried
+// without sending the others again.
+type Scheduler struct {
+	repo     *Repository
+	runner   *jobs.Runner
+	sender   mailer.Sender
+	tracer   trace.Tracer
+	interval time.Duration
+	appUrl   string
+	limit    int
+}
+
+func NewScheduler(repo *Repository, runner *jobs.Runner, sender mailer.Sender, tracer trace.Tracer) *Scheduler {
+	interval, err := time.ParseDuration(os.Getenv("DIGEST_SCHEDULE_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = time.Hour
+	}
+
+	appUrl := os.Getenv("APP_URL")
+	if appUrl == "" {
+		appUrl = "http://localhost:3000"
+	}
+
+	return &Scheduler{
+		repo:     repo,
+		runner:   runner,
+		sender:   sender,
+		tracer:   tracer,
+		interval: interval,
+		appUrl:   appUrl,
+		limit:    10,
+	}
+}
+
+func (s *Scheduler) Register() {
+	s.runner.Queue(mailer.Queue, 2)
+
+	jobs.Register(s.runner, s.schedule)
+	jobs.Register(s.runner, s.send)
+
+	s.runner.Every(s.interval, scheduleJob{}, jobvalue.Options{})
+}
+
+func (s *Scheduler) schedule(ctx context.Context, job scheduleJob) error {
+	ctx, span := s.tracer.Start(ctx, "digest.Scheduler.schedule")
+	defer span.End()
+
+	for _, frequency := range value.Frequencies {
+		start, _ := value.Period(frequency, time.Now())
+
+		due, err := s.repo.GetDue(ctx, frequency, start)
+		if err != nil {
+			return err
+		}
+
+		span.SetAttributes(attribute.Int(frequency, len(due)))
+
+		for _, accountId := range due {
+			send := sendJob{
+				AccountId:   accountId,
+				Frequency:   frequency,
+				PeriodStart: start,
+			}
+
+			err := s.runner.Enqueue(ctx, send, jobvalue.Options{
+				Queue:     mailer.Queue,
+				UniqueKey: fmt.Sprintf("%s:%s:%s:%d", send.Kind(), frequency, accountId, start.Unix()),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Scheduler) send(ctx context.Context, job sendJob) error {
+	ctx, span := s.tracer.Start(ctx, "digest.Scheduler.send")
+	defer span.End()
+
+	recipient, err := s.repo.GetRecipient(ctx, job.AccountId)
+
+	// unsubscribed since it was scheduled
+	if errors.Is(err, ErrSubscriptionNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// switched to another frequency since it was scheduled
+	if recipient.Frequency != job.Frequency {
+		return nil
+	}
+
+	start, end := job.PeriodStart, value.PeriodEnd(job.Frequency, job.PeriodStart)
+
+	questions, err := s.repo.GetTopQuestions(ctx, job.AccountId, start, end, s.limit)
+	if err != nil {
+		return err
+	}
+
+	span.SetAttributes(attribute.Int("questions", len(questions)))
+
+	_, err = s.repo.Deliver(ctx, recipient, start, len(questions), func() error {
+		// the period is still recorded so the empty digest is not built again
+		if len(questions) == 0 {
+			return nil
+		}
+
+		msg, err := Render(value.Digest{
+			Recipient:      recipient,
+			Frequency:      job.Frequency,
+			PeriodStart:    start,
+			PeriodEnd:      end,
+			Questions:      questions,
+			AppUrl:         s.appUrl,
+			UnsubscribeUrl: fmt.Sprintf("%s/digest/unsubscribe?token=%s", s.appUrl, url.QueryEscape(recipient.UnsubscribeToken)),
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.sender.Send(ctx, msg)
+	})
+
+	return err
+}
@@ -0,0 +1,72 @@
+package digest
+
+import (
+	"context"
+
+	"github.com/rizface/quora/digest/value"
+	"github.com/rizface/quora/identifier"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	Service struct {
+		tracer trace.Tracer
+		repo   *Repository
+	}
+
+	Input struct {
+		Identity            identifier.Claim
+		SubscriptionPayload value.SubscriptionPayload
+		Token               string
+	}
+)
+
+func NewService(repo *Repository, tracer trace.Tracer) *Service {
+	return &Service{
+		repo:   repo,
+		tracer: tracer,
+	}
+}
+
+func (s *Service) GetSubscription(ctx context.Context, input Input) (value.Subscription, error) {
+	ctx, span := s.tracer.Start(ctx, "digest.Service.GetSubscription")
+	defer span.End()
+
+	return s.repo.GetSubscription(ctx, input.Identity.AccountId)
+}
+
+func (s *Service) Subscribe(ctx context.Context, input Input) (value.Subscription, error) {
+	ctx, span := s.tracer.Start(ctx, "digest.Service.Subscribe")
+	defer span.End()
+
+	if err := value.ValidateSubscriptionPayload(input.SubscriptionPayload); err != nil {
+		return value.Subscription{}, err
+	}
+
+	subscription, err := value.NewSubscription(input.SubscriptionPayload, input.Identity.AccountId)
+	if err != nil {
+		return value.Subscription{}, err
+	}
+
+	return s.repo.Subscribe(ctx, subscription)
+}
+
+func (s *Service) Unsubscribe(ctx context.Context, input Input) error {
+	ctx, span := s.tracer.Start(ctx, "digest.Service.Unsubscribe")
+	defer span.End()
+
+	return s.repo.Unsubscribe(ctx, input.Identity.AccountId)
+}
+
+// UnsubscribeByToken serves the link of the emails, the recipient may not be
+// logged in.
+func (s *Service) UnsubscribeByToken(ctx context.Context, input Input) error {
+	ctx, span := s.tracer.Start(ctx, "digest.Service.UnsubscribeByToken")
+	defer span.End()
+
+	if input.Token == "" {
+		return ErrSubscriptionNotFound
+	}
+
+	return s.repo.UnsubscribeByToken(ctx, input.Token)
+}
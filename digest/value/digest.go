@@ -0,0 +1,114 @@
+package value
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	FrequencyDaily  = "daily"
+	FrequencyWeekly = "weekly"
+)
+
+// Frequencies lists the digests that are scheduled
+var Frequencies = []string{FrequencyDaily, FrequencyWeekly}
+
+type (
+	SubscriptionPayload struct {
+		Frequency string `json:"frequency"`
+	}
+
+	Subscription struct {
+		AccountId        string    `json:"accountId"`
+		Frequency        string    `json:"frequency"`
+		UnsubscribeToken string    `json:"-"`
+		CreatedAt        time.Time `json:"createdAt"`
+		UpdatedAt        time.Time `json:"updatedAt"`
+	}
+
+	Recipient struct {
+		AccountId        string
+		Username         string
+		Email            string
+		Frequency        string
+		UnsubscribeToken string
+	}
+
+	Question struct {
+		Id        string
+		Question  string
+		Tags      []string
+		Space     string
+		Answers   int
+		TopAnswer string
+	}
+
+	// Digest is the data of the email templates
+	Digest struct {
+		Recipient      Recipient
+		Frequency      string
+		PeriodStart    time.Time
+		PeriodEnd      time.Time
+		Questions      []Question
+		AppUrl         string
+		UnsubscribeUrl string
+	}
+)
+
+func NewSubscription(p SubscriptionPayload, accountId string) (Subscription, error) {
+	token, err := newToken()
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	return Subscription{
+		AccountId:        accountId,
+		Frequency:        p.Frequency,
+		UnsubscribeToken: token,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}, nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func ValidateSubscriptionPayload(p SubscriptionPayload) error {
+	return validation.Errors{
+		"frequency": validation.Validate(p.Frequency, validation.Required, validation.In(FrequencyDaily, FrequencyWeekly)),
+	}.Filter()
+}
+
+// PeriodEnd returns the end of the period that starts at start.
+func PeriodEnd(frequency string, start time.Time) time.Time {
+	if frequency == FrequencyWeekly {
+		return start.AddDate(0, 0, 7)
+	}
+
+	return start.AddDate(0, 0, 1)
+}
+
+// Period returns the last complete period of the frequency before now, in
+// UTC. A daily digest covers the previous day and a weekly digest the
+// previous week starting on monday.
+func Period(frequency string, now time.Time) (time.Time, time.Time) {
+	end := now.UTC().Truncate(24 * time.Hour)
+
+	if frequency == FrequencyWeekly {
+		// weekdays count from sunday
+		end = end.AddDate(0, 0, -((int(end.Weekday()) + 6) % 7))
+
+		return end.AddDate(0, 0, -7), end
+	}
+
+	return end.AddDate(0, 0, -1), end
+}
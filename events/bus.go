@@ -0,0 +1,171 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Event is a domain event, the name is used to route it to subscribers
+	Event interface {
+		Name() string
+	}
+
+	// Message is an event read back from the outbox
+	Message struct {
+		Id        string
+		Name      string
+		Payload   json.RawMessage
+		Attempts  int
+		CreatedAt time.Time
+	}
+
+	subscriber struct {
+		consumer string
+		handle   func(ctx context.Context, tx *sql.Tx, e Event) error
+	}
+
+	// Bus dispatches the events relayed from the outbox to the subscribers of
+	// the event name, so the feature emitting an event does not need to know
+	// who reacts to it.
+	Bus struct {
+		repo   *Repository
+		tracer trace.Tracer
+
+		mu          sync.RWMutex
+		decoders    map[string]func(payload json.RawMessage) (Event, error)
+		subscribers map[string][]subscriber
+	}
+)
+
+func NewBus(repo *Repository, tracer trace.Tracer) *Bus {
+	return &Bus{
+		repo:        repo,
+		tracer:      tracer,
+		decoders:    map[string]func(payload json.RawMessage) (Event, error){},
+		subscribers: map[string][]subscriber{},
+	}
+}
+
+// Subscribe registers the handler for the events of type T. The consumer
+// identifies the handler in processed_events, it must be unique and must not
+// change between releases or the events it already handled are handled again.
+// The handler writes with tx, which also records that it handled the event, so
+// its changes are committed once whatever happens to the relay.
+func Subscribe[T Event](b *Bus, consumer string, handle func(ctx context.Context, tx *sql.Tx, e T) error) {
+	var (
+		zero T
+		name = zero.Name()
+	)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.decoders[name] = decode[T]
+	b.subscribers[name] = append(b.subscribers[name], subscriber{
+		consumer: consumer,
+		handle: func(ctx context.Context, tx *sql.Tx, e Event) error {
+			event, ok := e.(T)
+			if !ok {
+				return fmt.Errorf("unexpected event %T for %s", e, name)
+			}
+
+			return handle(ctx, tx, event)
+		},
+	})
+}
+
+func decode[T Event](payload json.RawMessage) (Event, error) {
+	var e T
+
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Names returns the names of the events that have subscribers.
+func (b *Bus) Names() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	names := make([]string, 0, len(b.subscribers))
+	for name := range b.subscribers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Publish runs every subscriber of the message that has not handled it yet.
+// A failing subscriber does not stop the others, its error is returned so the
+// message is published again later and only the failed subscribers run again.
+func (b *Bus) Publish(ctx context.Context, m Message) error {
+	ctx, span := b.tracer.Start(ctx, "events.Bus.Publish")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("event", m.Name), attribute.String("event.id", m.Id))
+
+	b.mu.RLock()
+	decoder, ok := b.decoders[m.Name]
+	subscribers := b.subscribers[m.Name]
+	b.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("no subscriber for event %s", m.Name)
+	}
+
+	e, err := decoder(m.Payload)
+	if err != nil {
+		return fmt.Errorf("failed decode event %s: %w", m.Name, err)
+	}
+
+	var errs []error
+
+	for _, s := range subscribers {
+		if err := b.deliver(ctx, m, e, s); err != nil {
+			span.RecordError(err)
+			errs = append(errs, fmt.Errorf("%s: %w", s.consumer, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// deliver runs the subscriber in the transaction that marks the message as
+// processed by it. The mark is written first, so a subscriber that already
+// handled the message, or is handling it on another relay, is skipped.
+func (b *Bus) deliver(ctx context.Context, m Message, e Event, s subscriber) error {
+	tx, err := b.repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	marked, err := b.repo.MarkProcessed(ctx, tx, s.consumer, m.Id)
+	if err != nil {
+		return err
+	}
+
+	if !marked {
+		return nil
+	}
+
+	if err := s.handle(ctx, tx, e); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
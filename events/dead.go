@@ -0,0 +1,69 @@
+package events
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/rizface/quora/nuller"
+)
+
+type (
+	// DeadEvent is an event whose attempts ran out, it is only published
+	// again when it is retried
+	DeadEvent struct {
+		Id        string            `json:"id"`
+		Name      string            `json:"name"`
+		Payload   json.RawMessage   `json:"payload"`
+		Attempts  int               `json:"attempts"`
+		LastError nuller.NullString `json:"lastError"`
+		DeadAt    time.Time         `json:"deadAt"`
+		CreatedAt time.Time         `json:"createdAt"`
+	}
+
+	DeadQuery struct {
+		Limit int `json:"limit"`
+		Skip  int `json:"skip"`
+	}
+)
+
+func NewDeadQuery(url url.Values) (DeadQuery, error) {
+	q := DeadQuery{
+		Limit: 20,
+		Skip:  0,
+	}
+
+	if url.Has("limit") && url.Get("limit") != "" {
+		limit, err := strconv.Atoi(url.Get("limit"))
+		if err != nil {
+			return DeadQuery{}, err
+		}
+
+		q.Limit = limit
+	}
+
+	if url.Has("skip") && url.Get("skip") != "" {
+		skip, err := strconv.Atoi(url.Get("skip"))
+		if err != nil {
+			return DeadQuery{}, err
+		}
+
+		q.Skip = skip
+	}
+
+	return q, nil
+}
+
+func ValidateDeadQuery(q DeadQuery) error {
+	return validation.Errors{
+		"limit": validation.Validate(q.Limit, validation.Min(1), validation.Max(100)),
+		"skip":  validation.Validate(q.Skip, validation.Min(0)),
+	}.Filter()
+}
+
+func ValidateId(id string) error {
+	return validation.Validate(id, validation.Required, is.UUID)
+}
@@ -0,0 +1,8 @@
+package events
+
+import "errors"
+
+var (
+	ErrEventNotFound = errors.New("dead event not found")
+	ErrNotModerator  = errors.New("only moderators can manage events")
+)
@@ -0,0 +1,40 @@
+package events
+
+import (
+	"database/sql"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rizface/quora/identifier"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Feature struct {
+	handler *Handler
+	r       *chi.Mux
+}
+
+// NewFeature only serves the admin endpoints of the dead events, the bus and
+// the relay are shared by the features that emit and consume events.
+func NewFeature(r *chi.Mux, db *sql.DB, tracer trace.Tracer) *Feature {
+	var (
+		repo    = NewRepository(db, tracer)
+		svc     = NewService(repo, tracer)
+		handler = NewHandler(svc, tracer)
+	)
+
+	return &Feature{
+		handler: handler,
+		r:       r,
+	}
+}
+
+func (f *Feature) RegisterRoutes() {
+	f.r.Group(func(r chi.Router) {
+		r.Use(identifier.Identifier)
+
+		r.Route("/admin/events", func(r chi.Router) {
+			r.Get("/", f.handler.GetDeadEvents)
+			r.Post("/{id}/retry", f.handler.RetryEvent)
+		})
+	})
+}
@@ -0,0 +1,150 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/stdres"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Handler struct {
+	tracer trace.Tracer
+	svc    *Service
+}
+
+func NewHandler(svc *Service, tracer trace.Tracer) *Handler {
+	return &Handler{
+		tracer: tracer,
+		svc:    svc,
+	}
+}
+
+func (h *Handler) GetDeadEvents(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "events.Handler.GetDeadEvents")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	query, err := NewDeadQuery(r.URL.Query())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "invalid query parameter",
+		})
+
+		return
+	}
+
+	deadEvents, err := h.svc.GetDeadEvents(ctx, Input{
+		Identity:  *identity,
+		DeadQuery: query,
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrNotModerator) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusForbidden,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while get dead events: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{
+			"docs": deadEvents,
+		},
+	})
+}
+
+func (h *Handler) RetryEvent(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "events.Handler.RetryEvent")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	err = h.svc.RetryEvent(ctx, Input{
+		Identity: *identity,
+		EventId:  chi.URLParam(r, "id"),
+	})
+	if errors.Is(err, ErrNotModerator) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusForbidden,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrEventNotFound) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusNotFound,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while retry event: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+	})
+}
@@ -0,0 +1,74 @@
+package events
+
+const (
+	QuestionCreatedName      = "question.created"
+	QuestionUpdatedName      = "question.updated"
+	QuestionDeletedName      = "question.deleted"
+	QuestionStateChangedName = "question.state_changed"
+	AnswerCreatedName        = "answer.created"
+	AnswerVotedName          = "answer.voted"
+)
+
+type (
+	QuestionCreated struct {
+		QuestionId string   `json:"questionId"`
+		AuthorId   string   `json:"authorId"`
+		SpaceId    string   `json:"spaceId,omitempty"`
+		Question   string   `json:"question"`
+		Tags       []string `json:"tags"`
+	}
+
+	QuestionUpdated struct {
+		QuestionId string   `json:"questionId"`
+		AuthorId   string   `json:"authorId"`
+		SpaceId    string   `json:"spaceId,omitempty"`
+		Question   string   `json:"question"`
+		Tags       []string `json:"tags"`
+	}
+
+	QuestionDeleted struct {
+		QuestionId string `json:"questionId"`
+		AuthorId   string `json:"authorId"`
+		SpaceId    string `json:"spaceId,omitempty"`
+	}
+
+	// QuestionStateChanged is emitted when a question is closed, locked,
+	// reopened or marked as a duplicate
+	QuestionStateChanged struct {
+		QuestionId  string `json:"questionId"`
+		AuthorId    string `json:"authorId"`
+		ActorId     string `json:"actorId"`
+		State       string `json:"state"`
+		Reason      string `json:"reason,omitempty"`
+		DuplicateOf string `json:"duplicateOf,omitempty"`
+	}
+
+	AnswerCreated struct {
+		AnswerId         string `json:"answerId"`
+		QuestionId       string `json:"questionId"`
+		QuestionAuthorId string `json:"questionAuthorId"`
+		SpaceId          string `json:"spaceId,omitempty"`
+		AnswererId       string `json:"answererId"`
+		Answer           string `json:"answer"`
+	}
+
+	AnswerVoted struct {
+		AnswerId         string  `json:"answerId"`
+		QuestionId       string  `json:"questionId"`
+		QuestionAuthorId string  `json:"questionAuthorId"`
+		SpaceId          string  `json:"spaceId,omitempty"`
+		AnswererId       string  `json:"answererId"`
+		VoterId          string  `json:"voterId"`
+		Type             string  `json:"type"`
+		Upvote           int     `json:"upvote"`
+		Downvote         int     `json:"downvote"`
+		Score            float64 `json:"score"`
+	}
+)
+
+func (QuestionCreated) Name() string      { return QuestionCreatedName }
+func (QuestionUpdated) Name() string      { return QuestionUpdatedName }
+func (QuestionDeleted) Name() string      { return QuestionDeletedName }
+func (QuestionStateChanged) Name() string { return QuestionStateChangedName }
+func (AnswerCreated) Name() string        { return AnswerCreatedName }
+func (AnswerVoted) Name() string          { return AnswerVotedName }
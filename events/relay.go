@@ -0,0 +1,231 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rizface/quora/periodic"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// notified by a trigger on the events table
+const recordedChannel = "events_recorded"
+
+// Relay publishes the events of the outbox to the bus. An event is marked as
+// published in the transaction that claimed it, if the process dies before the
+// transaction commits the event is claimed and published again, so every event
+// is published at least once. Events are not ordered across retries, an event
+// that fails EVENTS_MAX_ATTEMPTS times is dead until a moderator retries it.
+type Relay struct {
+	db        *sql.DB
+	dsn       string
+	repo      *Repository
+	bus       *Bus
+	tracer    trace.Tracer
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	// the events that fail that many attempts are dead
+	maxAttempts int
+	retention   time.Duration
+	batch       int
+
+	listener *pq.Listener
+	loop     *periodic.Worker
+	// only read and written by the loop
+	purgedAt time.Time
+}
+
+func NewRelay(db *sql.DB, dsn string, repo *Repository, bus *Bus, tracer trace.Tracer) *Relay {
+	interval, err := time.ParseDuration(os.Getenv("EVENTS_RELAY_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	baseDelay, err := time.ParseDuration(os.Getenv("EVENTS_RETRY_BASE_DELAY"))
+	if err != nil || baseDelay <= 0 {
+		baseDelay = 5 * time.Second
+	}
+
+	maxAttempts, err := strconv.Atoi(os.Getenv("EVENTS_MAX_ATTEMPTS"))
+	if err != nil || maxAttempts <= 0 {
+		maxAttempts = 10
+	}
+
+	retention, err := time.ParseDuration(os.Getenv("EVENTS_RETENTION"))
+	if err != nil || retention <= 0 {
+		retention = 7 * 24 * time.Hour
+	}
+
+	return &Relay{
+		db:          db,
+		dsn:         dsn,
+		repo:        repo,
+		bus:         bus,
+		tracer:      tracer,
+		baseDelay:   baseDelay,
+		maxDelay:    time.Hour,
+		maxAttempts: maxAttempts,
+		retention:   retention,
+		batch:       100,
+		loop:        periodic.NewWorker(interval),
+	}
+}
+
+// Start relays the events as soon as they are recorded, the interval only
+// catches the retries and the notifications lost while the listener was
+// reconnecting.
+func (r *Relay) Start() {
+	r.listener = pq.NewListener(r.dsn, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("events listener: %v", err)
+		}
+	})
+
+	if err := r.listener.Listen(recordedChannel); err != nil {
+		log.Printf("failed listen to %s: %v", recordedChannel, err)
+	}
+
+	// the notify channel is closed with the listener
+	go func() {
+		for range r.listener.Notify {
+			r.loop.Wake()
+		}
+	}()
+
+	r.purgedAt = time.Now()
+
+	r.loop.Start(func(ctx context.Context) {
+		if _, err := r.Flush(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("failed relay events: %v", err)
+		}
+
+		if time.Since(r.purgedAt) >= time.Hour {
+			r.purge(ctx)
+			r.purgedAt = time.Now()
+		}
+	})
+}
+
+func (r *Relay) Stop(ctx context.Context) error {
+	if r.listener == nil {
+		return nil
+	}
+
+	if err := r.loop.Stop(ctx); err != nil {
+		return err
+	}
+
+	return r.listener.Close()
+}
+
+// Flush publishes the due events until none is left and returns the number of
+// events published.
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	ctx, span := r.tracer.Start(ctx, "events.Relay.Flush")
+	defer span.End()
+
+	total := 0
+
+	for {
+		published, claimed, err := r.relay(ctx)
+		total += published
+
+		if err != nil {
+			span.RecordError(err)
+			return total, err
+		}
+
+		if claimed < r.batch {
+			span.SetAttributes(attribute.Int("published", total))
+			return total, nil
+		}
+	}
+}
+
+// relay publishes one batch, the events stay locked by the transaction while
+// their subscribers run.
+func (r *Relay) relay(ctx context.Context) (published, claimed int, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	messages, err := r.repo.Claim(ctx, tx, r.bus.Names(), r.batch)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, m := range messages {
+		if err := r.bus.Publish(ctx, m); err != nil {
+			// the relay is stopping, the transaction is rolled back and
+			// the events are published again by the next relay
+			if ctx.Err() != nil {
+				return 0, len(messages), ctx.Err()
+			}
+
+			log.Printf("failed publish event %s %s: %v", m.Name, m.Id, err)
+
+			// the consumers that processed it are not run again when a
+			// moderator retries it
+			if m.Attempts+1 >= r.maxAttempts {
+				if err := r.repo.MarkDead(ctx, tx, m.Id, err); err != nil {
+					return 0, len(messages), err
+				}
+
+				continue
+			}
+
+			nextAttemptAt := time.Now().Add(r.backoff(m.Attempts + 1))
+			if err := r.repo.Retry(ctx, tx, m.Id, err, nextAttemptAt); err != nil {
+				return 0, len(messages), err
+			}
+
+			continue
+		}
+
+		if err := r.repo.MarkPublished(ctx, tx, m.Id); err != nil {
+			return 0, len(messages), err
+		}
+
+		published++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, len(messages), err
+	}
+
+	return published, len(messages), nil
+}
+
+// backoff doubles the delay on every failed attempt up to maxDelay.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := time.Duration(float64(r.baseDelay) * math.Pow(2, float64(attempts-1)))
+	if delay <= 0 || delay > r.maxDelay {
+		return r.maxDelay
+	}
+
+	return delay
+}
+
+func (r *Relay) purge(ctx context.Context) {
+	ctx, span := r.tracer.Start(ctx, "events.Relay.purge")
+	defer span.End()
+
+	deleted, err := r.repo.Purge(ctx, time.Now().Add(-r.retention))
+	if err != nil {
+		span.RecordError(err)
+		log.Printf("failed purge events: %v", err)
+
+		return
+	}
+
+	span.SetAttributes(attribute.Int64("deleted", deleted))
+}
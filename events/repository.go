@@ -0,0 +1,250 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Record writes the event to the outbox with the transaction of the domain
+// change, so the event is published if and only if the change is committed.
+func Record(ctx context.Context, tx *sql.Tx, e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	command := `
+		INSERT INTO events (id, name, payload) VALUES ($1, $2, $3)
+	`
+
+	_, err = tx.ExecContext(ctx, command, uuid.NewString(), e.Name(), payload)
+
+	return err
+}
+
+type Repository struct {
+	db     *sql.DB
+	tracer trace.Tracer
+}
+
+func NewRepository(db *sql.DB, tracer trace.Tracer) *Repository {
+	return &Repository{
+		db:     db,
+		tracer: tracer,
+	}
+}
+
+// Claim locks the unpublished events that are due until tx ends, events locked
+// by another relay and dead events are skipped.
+func (r *Repository) Claim(ctx context.Context, tx *sql.Tx, names []string, batch int) ([]Message, error) {
+	ctx, span := r.tracer.Start(ctx, "events.Repository.Claim")
+	defer span.End()
+
+	var (
+		messages = []Message{}
+		query    = `
+			SELECT id, name, payload, attempts, created_at FROM events
+			WHERE published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= now() AND name = ANY($1)
+			ORDER BY created_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		`
+	)
+
+	rows, err := tx.QueryContext(ctx, query, pq.Array(names), batch)
+	if err != nil {
+		return messages, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m Message
+
+		if err := rows.Scan(&m.Id, &m.Name, &m.Payload, &m.Attempts, &m.CreatedAt); err != nil {
+			return messages, err
+		}
+
+		messages = append(messages, m)
+	}
+
+	return messages, rows.Err()
+}
+
+func (r *Repository) MarkPublished(ctx context.Context, tx *sql.Tx, id string) error {
+	ctx, span := r.tracer.Start(ctx, "events.Repository.MarkPublished")
+	defer span.End()
+
+	command := `
+		UPDATE events SET published_at = now(), attempts = attempts + 1, last_error = NULL WHERE id = $1
+	`
+
+	_, err := tx.ExecContext(ctx, command, id)
+
+	return err
+}
+
+// Retry records the failure, the event is published again at nextAttemptAt.
+func (r *Repository) Retry(ctx context.Context, tx *sql.Tx, id string, cause error, nextAttemptAt time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "events.Repository.Retry")
+	defer span.End()
+
+	command := `
+		UPDATE events SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2 WHERE id = $3
+	`
+
+	_, err := tx.ExecContext(ctx, command, cause.Error(), nextAttemptAt, id)
+
+	return err
+}
+
+// MarkDead records the failure of the last attempt, the event is not claimed
+// again until it is retried.
+func (r *Repository) MarkDead(ctx context.Context, tx *sql.Tx, id string, cause error) error {
+	ctx, span := r.tracer.Start(ctx, "events.Repository.MarkDead")
+	defer span.End()
+
+	command := `
+		UPDATE events SET attempts = attempts + 1, last_error = $1, dead_at = now() WHERE id = $2
+	`
+
+	_, err := tx.ExecContext(ctx, command, cause.Error(), id)
+
+	return err
+}
+
+func (r *Repository) GetDead(ctx context.Context, q DeadQuery) ([]DeadEvent, error) {
+	ctx, span := r.tracer.Start(ctx, "events.Repository.GetDead")
+	defer span.End()
+
+	var (
+		deadEvents = []DeadEvent{}
+		query      = `
+			SELECT id, name, payload, attempts, last_error, dead_at, created_at FROM events
+			WHERE dead_at IS NOT NULL
+			ORDER BY dead_at DESC
+			LIMIT $1 OFFSET $2
+		`
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, q.Limit, q.Skip)
+	if err != nil {
+		return deadEvents, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e DeadEvent
+
+		err := rows.Scan(&e.Id, &e.Name, &e.Payload, &e.Attempts, &e.LastError, &e.DeadAt, &e.CreatedAt)
+		if err != nil {
+			return deadEvents, err
+		}
+
+		deadEvents = append(deadEvents, e)
+	}
+
+	return deadEvents, rows.Err()
+}
+
+// Revive publishes a dead event again with a fresh set of attempts, the
+// consumers that already processed it are skipped.
+func (r *Repository) Revive(ctx context.Context, id string) error {
+	ctx, span := r.tracer.Start(ctx, "events.Repository.Revive")
+	defer span.End()
+
+	command := `
+		UPDATE events SET dead_at = NULL, attempts = 0, next_attempt_at = now() WHERE id = $1 AND dead_at IS NOT NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, command, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrEventNotFound
+	}
+
+	return nil
+}
+
+// MarkProcessed returns false when the consumer already processed the event.
+// The row of a consumer that is processing the event in another transaction
+// blocks until that transaction ends.
+func (r *Repository) MarkProcessed(ctx context.Context, tx *sql.Tx, consumer, eventId string) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "events.Repository.MarkProcessed")
+	defer span.End()
+
+	command := `
+		INSERT INTO processed_events (consumer, event_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+	`
+
+	result, err := tx.ExecContext(ctx, command, consumer, eventId)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+
+	return affected == 1, err
+}
+
+// Purge deletes the published events and the bookkeeping of the consumers
+// once they are older than the retention, the bookkeeping of events that are
+// still retried or dead is kept. Dead events are kept until they are retried.
+func (r *Repository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "events.Repository.Purge")
+	defer span.End()
+
+	command := `
+		DELETE FROM processed_events p WHERE p.processed_at < $1 AND NOT EXISTS (
+			SELECT 1 FROM events e WHERE e.id = p.event_id AND e.published_at IS NULL
+		)
+	`
+
+	if _, err := r.db.ExecContext(ctx, command, before); err != nil {
+		return 0, err
+	}
+
+	command = `
+		DELETE FROM events WHERE published_at < $1
+	`
+
+	result, err := r.db.ExecContext(ctx, command, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *Repository) IsModerator(ctx context.Context, accountId string) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "events.Repository.IsModerator")
+	defer span.End()
+
+	var (
+		isModerator bool
+		query       = `
+			SELECT role IN ('moderator', 'admin') FROM accounts WHERE id = $1
+		`
+	)
+
+	err := r.db.QueryRowContext(ctx, query, accountId).Scan(&isModerator)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	return isModerator, err
+}
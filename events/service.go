@@ -0,0 +1,72 @@
+package events
+
+import (
+	"context"
+
+	"github.com/rizface/quora/identifier"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	Service struct {
+		tracer trace.Tracer
+		repo   *Repository
+	}
+
+	Input struct {
+		Identity  identifier.Claim
+		EventId   string
+		DeadQuery DeadQuery
+	}
+)
+
+func NewService(repo *Repository, tracer trace.Tracer) *Service {
+	return &Service{
+		repo:   repo,
+		tracer: tracer,
+	}
+}
+
+func (s *Service) ensureModerator(ctx context.Context, identity identifier.Claim) error {
+	isModerator, err := s.repo.IsModerator(ctx, identity.AccountId)
+	if err != nil {
+		return err
+	}
+
+	if !isModerator {
+		return ErrNotModerator
+	}
+
+	return nil
+}
+
+func (s *Service) GetDeadEvents(ctx context.Context, input Input) ([]DeadEvent, error) {
+	ctx, span := s.tracer.Start(ctx, "events.Service.GetDeadEvents")
+	defer span.End()
+
+	if err := ValidateDeadQuery(input.DeadQuery); err != nil {
+		return []DeadEvent{}, err
+	}
+
+	if err := s.ensureModerator(ctx, input.Identity); err != nil {
+		return []DeadEvent{}, err
+	}
+
+	return s.repo.GetDead(ctx, input.DeadQuery)
+}
+
+// RetryEvent is picked up by the next pass of the relay.
+func (s *Service) RetryEvent(ctx context.Context, input Input) error {
+	ctx, span := s.tracer.Start(ctx, "events.Service.RetryEvent")
+	defer span.End()
+
+	if err := s.ensureModerator(ctx, input.Identity); err != nil {
+		return err
+	}
+
+	if err := ValidateId(input.EventId); err != nil {
+		return ErrEventNotFound
+	}
+
+	return s.repo.Revive(ctx, input.EventId)
+}
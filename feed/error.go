@@ -0,0 +1,8 @@
+package feed
+
+import "errors"
+
+var (
+	ErrFollowNotFound = errors.New("follow not found")
+	ErrSelfFollow     = errors.New("can not follow yourself")
+)
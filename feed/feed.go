@@ -0,0 +1,43 @@
+package feed
+
+import (
+	"database/sql"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rizface/quora/identifier"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Feature struct {
+	handler *Handler
+	r       *chi.Mux
+	Worker  *Worker
+}
+
+func NewFeature(r *chi.Mux, db *sql.DB, tracer trace.Tracer) *Feature {
+	var (
+		repo    = NewRepository(db, tracer)
+		svc     = NewService(repo, tracer)
+		handler = NewHandler(svc, tracer)
+	)
+
+	return &Feature{
+		handler: handler,
+		r:       r,
+		Worker:  NewWorker(repo, tracer),
+	}
+}
+
+func (f *Feature) RegisterRoutes() {
+	f.r.Group(func(r chi.Router) {
+		r.Use(identifier.Identifier)
+
+		r.Get("/feed", f.handler.GetFeed)
+
+		r.Route("/follows", func(r chi.Router) {
+			r.Get("/", f.handler.GetFollows)
+			r.Post("/", f.handler.Follow)
+			r.Delete("/{type}/{targetId}", f.handler.Unfollow)
+		})
+	})
+}
@@ -0,0 +1,252 @@
+package feed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/rizface/quora/feed/value"
+	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/stdres"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Handler struct {
+	tracer trace.Tracer
+	svc    *Service
+}
+
+func NewHandler(svc *Service, tracer trace.Tracer) *Handler {
+	return &Handler{
+		tracer: tracer,
+		svc:    svc,
+	}
+}
+
+func (h *Handler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "feed.Handler.GetFeed")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	query, err := value.NewFeedQuery(r.URL.Query())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "invalid query parameter",
+		})
+
+		return
+	}
+
+	items, err := h.svc.GetFeed(ctx, Input{
+		Identity:  *identity,
+		FeedQuery: query,
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while get feed: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{"docs": items},
+	})
+}
+
+func (h *Handler) Follow(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "feed.Handler.Follow")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	var payload value.FollowPayload
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "failed decode payload",
+		})
+
+		return
+	}
+
+	follow, err := h.svc.Follow(ctx, Input{
+		Identity:      *identity,
+		FollowPayload: payload,
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrSelfFollow) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while follow: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{"doc": follow},
+	})
+}
+
+func (h *Handler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "feed.Handler.Unfollow")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	err = h.svc.Unfollow(ctx, Input{
+		Identity: *identity,
+		FollowPayload: value.FollowPayload{
+			Type:     chi.URLParam(r, "type"),
+			TargetId: chi.URLParam(r, "targetId"),
+		},
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrFollowNotFound) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusNotFound,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while unfollow: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+	})
+}
+
+func (h *Handler) GetFollows(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "feed.Handler.GetFollows")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	follows, err := h.svc.GetFollows(ctx, Input{
+		Identity: *identity,
+	})
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while get follows: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{"docs": follows},
+	})
+}
@@ -0,0 +1,331 @@
+package feed
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rizface/quora/feed/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// number of existing questions copied into the feed when a new source is followed
+const backfillSize = 50
+
+type Repository struct {
+	db     *sql.DB
+	tracer trace.Tracer
+}
+
+func NewRepository(db *sql.DB, tracer trace.Tracer) *Repository {
+	return &Repository{
+		db:     db,
+		tracer: tracer,
+	}
+}
+
+func scanItems(rows *sql.Rows) ([]value.Item, error) {
+	defer rows.Close()
+
+	items := []value.Item{}
+
+	for rows.Next() {
+		item := value.Item{}
+
+		err := rows.Scan(
+			&item.Question.Id,
+			&item.Question.AuthorId,
+			&item.Question.SpaceId,
+			&item.Question.Question,
+			pq.Array(&item.Question.Tags),
+			&item.Question.CreatedAt,
+			&item.Reason,
+		)
+		if err != nil {
+			return []value.Item{}, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// Follow stores the follow and copies the latest questions of the followed
+// source into the feed, so the feed is not empty until new questions arrive.
+func (r *Repository) Follow(ctx context.Context, f value.Follow) error {
+	ctx, span := r.tracer.Start(ctx, "feed.Repository.Follow")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	command := `
+		INSERT INTO follows (follower_id, target_type, target_id, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err := tx.ExecContext(ctx, command, f.FollowerId, f.Type, f.TargetId, f.CreatedAt); err != nil {
+		return err
+	}
+
+	command = `
+		INSERT INTO feed_items (account_id, question_id, reason, created_at)
+		SELECT $1, q.id, $2::text, q.created_at FROM questions q
+		WHERE q.author_id <> $1 AND q.duplicate_of IS NULL AND (
+			($2::text = 'account' AND q.author_id::text = $3)
+			OR ($2::text = 'space' AND q.space_id::text = $3)
+			OR ($2::text = 'tag' AND $3 = ANY(q.tags))
+		)
+		ORDER BY q.created_at DESC
+		LIMIT $4
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err := tx.ExecContext(ctx, command, f.FollowerId, f.Type, f.TargetId, backfillSize); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Unfollow removes the feed items of the source with the follow, unless another
+// follow of the account still brings them.
+func (r *Repository) Unfollow(ctx context.Context, f value.Follow) error {
+	ctx, span := r.tracer.Start(ctx, "feed.Repository.Unfollow")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	command := `
+		DELETE FROM follows WHERE follower_id = $1 AND target_type = $2 AND target_id = $3
+	`
+
+	result, err := tx.ExecContext(ctx, command, f.FollowerId, f.Type, f.TargetId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrFollowNotFound
+	}
+
+	command = `
+		DELETE FROM feed_items fi USING questions q
+		WHERE fi.account_id = $1 AND q.id = fi.question_id AND (
+			($2::text = 'account' AND q.author_id::text = $3)
+			OR ($2::text = 'space' AND q.space_id::text = $3)
+			OR ($2::text = 'tag' AND $3 = ANY(q.tags))
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM follows f WHERE f.follower_id = $1 AND (
+				(f.target_type = 'account' AND f.target_id = q.author_id::text)
+				OR (f.target_type = 'space' AND f.target_id = q.space_id::text)
+				OR (f.target_type = 'tag' AND f.target_id = ANY(q.tags))
+			)
+		)
+	`
+
+	if _, err := tx.ExecContext(ctx, command, f.FollowerId, f.Type, f.TargetId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetFollows(ctx context.Context, followerId string) ([]value.Follow, error) {
+	ctx, span := r.tracer.Start(ctx, "feed.Repository.GetFollows")
+	defer span.End()
+
+	var (
+		follows = []value.Follow{}
+		query   = `
+			SELECT follower_id, target_type, target_id, created_at FROM follows
+			WHERE follower_id = $1 ORDER BY created_at DESC
+		`
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, followerId)
+	if err != nil {
+		return follows, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		follow := value.Follow{}
+
+		if err := rows.Scan(&follow.FollowerId, &follow.Type, &follow.TargetId, &follow.CreatedAt); err != nil {
+			return []value.Follow{}, err
+		}
+
+		follows = append(follows, follow)
+	}
+
+	return follows, rows.Err()
+}
+
+// GetFollowed returns the unseen questions that were fanned out to the account.
+func (r *Repository) GetFollowed(ctx context.Context, accountId string, limit int) ([]value.Item, error) {
+	ctx, span := r.tracer.Start(ctx, "feed.Repository.GetFollowed")
+	defer span.End()
+
+	query := `
+		SELECT q.id, q.author_id, q.space_id, q.question, q.tags, q.created_at, fi.reason
+		FROM feed_items fi
+		INNER JOIN questions q ON q.id = fi.question_id
+		WHERE fi.account_id = $1 AND q.duplicate_of IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM feed_seen s WHERE s.account_id = $1 AND s.question_id = fi.question_id
+		)
+		ORDER BY q.hot_score DESC NULLS LAST, q.created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, accountId, limit)
+	if err != nil {
+		return []value.Item{}, err
+	}
+
+	return scanItems(rows)
+}
+
+// GetTrending returns the hottest questions the account has not seen yet, it
+// is used to fill the feed when the followed sources run dry.
+func (r *Repository) GetTrending(ctx context.Context, accountId string, exclude []string, limit int) ([]value.Item, error) {
+	ctx, span := r.tracer.Start(ctx, "feed.Repository.GetTrending")
+	defer span.End()
+
+	query := `
+		SELECT q.id, q.author_id, q.space_id, q.question, q.tags, q.created_at, $4::text
+		FROM questions q
+		WHERE q.author_id <> $1 AND q.duplicate_of IS NULL AND NOT (q.id = ANY($2::uuid[]))
+		AND NOT EXISTS (
+			SELECT 1 FROM feed_seen s WHERE s.account_id = $1 AND s.question_id = q.id
+		)
+		ORDER BY q.hot_score DESC NULLS LAST, q.created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, accountId, pq.Array(exclude), limit, value.ReasonTrending)
+	if err != nil {
+		return []value.Item{}, err
+	}
+
+	return scanItems(rows)
+}
+
+func (r *Repository) MarkSeen(ctx context.Context, accountId string, questionIds []string) error {
+	ctx, span := r.tracer.Start(ctx, "feed.Repository.MarkSeen")
+	defer span.End()
+
+	command := `
+		INSERT INTO feed_seen (account_id, question_id) SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, command, accountId, pq.Array(questionIds))
+
+	return err
+}
+
+// FanOut copies the next batch of new questions into the feed of every
+// follower of their author, space or tags, then moves the cursor past the
+// batch. Questions younger than a few seconds are left for the next run, so a
+// transaction that commits late can not be skipped by the cursor.
+func (r *Repository) FanOut(ctx context.Context, batch int) (int, error) {
+	ctx, span := r.tracer.Start(ctx, "feed.Repository.FanOut")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var (
+		lastCreatedAt  time.Time
+		lastQuestionId string
+		query          = `
+			SELECT last_created_at, last_question_id FROM feed_cursor WHERE id = 'fanout' FOR UPDATE
+		`
+	)
+
+	if err := tx.QueryRowContext(ctx, query).Scan(&lastCreatedAt, &lastQuestionId); err != nil {
+		return 0, err
+	}
+
+	query = `
+		WITH batch AS (
+			SELECT id, author_id, space_id, tags, created_at FROM questions
+			WHERE (created_at, id) > ($1, $2::uuid)
+			AND created_at < CURRENT_TIMESTAMP - INTERVAL '5 seconds'
+			ORDER BY created_at, id
+			LIMIT $3
+		), fanned_out AS (
+			INSERT INTO feed_items (account_id, question_id, reason, created_at)
+			SELECT DISTINCT ON (f.follower_id, b.id) f.follower_id, b.id, f.target_type, b.created_at
+			FROM batch b
+			INNER JOIN follows f ON (f.target_type = 'account' AND f.target_id = b.author_id::text)
+				OR (f.target_type = 'space' AND f.target_id = b.space_id::text)
+				OR (f.target_type = 'tag' AND f.target_id = ANY(b.tags))
+			WHERE f.follower_id <> b.author_id
+			ORDER BY f.follower_id, b.id
+			ON CONFLICT DO NOTHING
+		)
+		SELECT created_at, id, (SELECT COUNT(*) FROM batch) FROM batch ORDER BY created_at DESC, id DESC LIMIT 1
+	`
+
+	var processed int
+
+	err = tx.
+		QueryRowContext(ctx, query, lastCreatedAt, lastQuestionId, batch).
+		Scan(&lastCreatedAt, &lastQuestionId, &processed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	command := `
+		UPDATE feed_cursor SET last_created_at = $1, last_question_id = $2 WHERE id = 'fanout'
+	`
+
+	if _, err := tx.ExecContext(ctx, command, lastCreatedAt, lastQuestionId); err != nil {
+		return 0, err
+	}
+
+	return processed, tx.Commit()
+}
+
+// Purge removes feed items and seen marks older than the retention, a question
+// that old is not shown in the feed anymore anyway.
+func (r *Repository) Purge(ctx context.Context, retention time.Duration) error {
+	ctx, span := r.tracer.Start(ctx, "feed.Repository.Purge")
+	defer span.End()
+
+	before := time.Now().Add(-retention)
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM feed_items WHERE created_at < $1`, before); err != nil {
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx, `DELETE FROM feed_seen WHERE seen_at < $1`, before)
+
+	return err
+}
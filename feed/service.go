@@ -0,0 +1,121 @@
+package feed
+
+import (
+	"context"
+
+	"github.com/rizface/quora/feed/value"
+	"github.com/rizface/quora/identifier"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	Service struct {
+		tracer trace.Tracer
+		repo   *Repository
+	}
+
+	Input struct {
+		Identity      identifier.Claim
+		FollowPayload value.FollowPayload
+		FeedQuery     value.FeedQuery
+	}
+)
+
+func NewService(repo *Repository, tracer trace.Tracer) *Service {
+	return &Service{
+		repo:   repo,
+		tracer: tracer,
+	}
+}
+
+func (s *Service) Follow(ctx context.Context, input Input) (value.Follow, error) {
+	ctx, span := s.tracer.Start(ctx, "feed.Service.Follow")
+	defer span.End()
+
+	follow := value.NewFollow(input.FollowPayload, input.Identity.AccountId)
+
+	if err := value.ValidateFollow(follow); err != nil {
+		return value.Follow{}, err
+	}
+
+	if follow.IsSelfFollow() {
+		return value.Follow{}, ErrSelfFollow
+	}
+
+	if err := s.repo.Follow(ctx, follow); err != nil {
+		return value.Follow{}, err
+	}
+
+	return follow, nil
+}
+
+func (s *Service) Unfollow(ctx context.Context, input Input) error {
+	ctx, span := s.tracer.Start(ctx, "feed.Service.Unfollow")
+	defer span.End()
+
+	follow := value.NewFollow(input.FollowPayload, input.Identity.AccountId)
+
+	if err := value.ValidateFollow(follow); err != nil {
+		return err
+	}
+
+	return s.repo.Unfollow(ctx, follow)
+}
+
+func (s *Service) GetFollows(ctx context.Context, input Input) ([]value.Follow, error) {
+	ctx, span := s.tracer.Start(ctx, "feed.Service.GetFollows")
+	defer span.End()
+
+	return s.repo.GetFollows(ctx, input.Identity.AccountId)
+}
+
+// GetFeed returns the unseen questions from the followed sources first and
+// fills the rest of the page with trending questions. Every returned question
+// is marked as seen, so the next call continues where this one stopped.
+func (s *Service) GetFeed(ctx context.Context, input Input) ([]value.Item, error) {
+	ctx, span := s.tracer.Start(ctx, "feed.Service.GetFeed")
+	defer span.End()
+
+	var (
+		accountId = input.Identity.AccountId
+		limit     = input.FeedQuery.Limit
+	)
+
+	if err := value.ValidateFeedQuery(input.FeedQuery); err != nil {
+		return []value.Item{}, err
+	}
+
+	items, err := s.repo.GetFollowed(ctx, accountId, limit)
+	if err != nil {
+		return []value.Item{}, err
+	}
+
+	if len(items) < limit {
+		ids := make([]string, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, item.Question.Id)
+		}
+
+		trending, err := s.repo.GetTrending(ctx, accountId, ids, limit-len(items))
+		if err != nil {
+			return []value.Item{}, err
+		}
+
+		items = append(items, trending...)
+	}
+
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	seen := make([]string, 0, len(items))
+	for _, item := range items {
+		seen = append(seen, item.Question.Id)
+	}
+
+	if err := s.repo.MarkSeen(ctx, accountId, seen); err != nil {
+		return []value.Item{}, err
+	}
+
+	return items, nil
+}
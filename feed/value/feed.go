@@ -0,0 +1,110 @@
+package value
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/rizface/quora/nuller"
+)
+
+const (
+	TargetTag     = "tag"
+	TargetSpace   = "space"
+	TargetAccount = "account"
+
+	// ReasonTrending marks items that do not come from a followed source
+	ReasonTrending = "trending"
+)
+
+type (
+	FollowPayload struct {
+		Type     string `json:"type"`
+		TargetId string `json:"targetId"`
+	}
+
+	Follow struct {
+		FollowerId string    `json:"followerId"`
+		Type       string    `json:"type"`
+		TargetId   string    `json:"targetId"`
+		CreatedAt  time.Time `json:"createdAt"`
+	}
+
+	Question struct {
+		Id        string            `json:"id"`
+		AuthorId  string            `json:"authorId"`
+		SpaceId   nuller.NullString `json:"spaceId"`
+		Question  string            `json:"question"`
+		Tags      []string          `json:"tags"`
+		CreatedAt time.Time         `json:"createdAt"`
+	}
+
+	Item struct {
+		Question Question `json:"question"`
+		// Reason tells why the question is in the feed: the type of the
+		// followed source or "trending"
+		Reason string `json:"reason"`
+	}
+
+	FeedQuery struct {
+		Limit int
+	}
+)
+
+func NewFollow(p FollowPayload, followerId string) Follow {
+	follow := Follow{
+		FollowerId: followerId,
+		Type:       p.Type,
+		TargetId:   strings.TrimSpace(p.TargetId),
+		CreatedAt:  time.Now(),
+	}
+
+	// tags are stored lowercased on questions
+	if follow.Type == TargetTag {
+		follow.TargetId = strings.ToLower(follow.TargetId)
+	}
+
+	return follow
+}
+
+func ValidateFollow(f Follow) error {
+	targetRules := []validation.Rule{validation.Required, validation.Length(1, 30)}
+	if f.Type == TargetSpace || f.Type == TargetAccount {
+		targetRules = []validation.Rule{validation.Required, is.UUID}
+	}
+
+	return validation.Errors{
+		"type":     validation.Validate(f.Type, validation.Required, validation.In(TargetTag, TargetSpace, TargetAccount)),
+		"targetId": validation.Validate(f.TargetId, targetRules...),
+	}.Filter()
+}
+
+func (f Follow) IsSelfFollow() bool {
+	return f.Type == TargetAccount && f.TargetId == f.FollowerId
+}
+
+func NewFeedQuery(url url.Values) (FeedQuery, error) {
+	q := FeedQuery{
+		Limit: 20,
+	}
+
+	if url.Has("limit") && url.Get("limit") != "" {
+		limit, err := strconv.Atoi(url.Get("limit"))
+		if err != nil {
+			return FeedQuery{}, err
+		}
+
+		q.Limit = limit
+	}
+
+	return q, nil
+}
+
+func ValidateFeedQuery(q FeedQuery) error {
+	return validation.Errors{
+		"limit": validation.Validate(q.Limit, validation.Min(1), validation.Max(100)),
+	}.Filter()
+}
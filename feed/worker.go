@@ -0,0 +1,84 @@
+package feed
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/rizface/quora/periodic"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Worker builds the feeds incrementally: every run only fans out the questions
+// created since the previous run, instead of joining every follow on every
+// feed request.
+type Worker struct {
+	repo      *Repository
+	tracer    trace.Tracer
+	retention time.Duration
+	batch     int
+
+	loop *periodic.Worker
+}
+
+func NewWorker(repo *Repository, tracer trace.Tracer) *Worker {
+	interval, err := time.ParseDuration(os.Getenv("FEED_FANOUT_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	retention, err := time.ParseDuration(os.Getenv("FEED_RETENTION"))
+	if err != nil || retention <= 0 {
+		retention = 30 * 24 * time.Hour
+	}
+
+	return &Worker{
+		repo:      repo,
+		tracer:    tracer,
+		retention: retention,
+		batch:     500,
+		loop:      periodic.NewWorker(interval),
+	}
+}
+
+func (w *Worker) Start() {
+	var lastPurge time.Time
+
+	w.loop.Start(func(ctx context.Context) {
+		w.fanOut(ctx)
+
+		if time.Since(lastPurge) > time.Hour {
+			if err := w.repo.Purge(ctx, w.retention); err != nil && ctx.Err() == nil {
+				log.Printf("failed purge feed: %v", err)
+			}
+
+			lastPurge = time.Now()
+		}
+	})
+}
+
+func (w *Worker) fanOut(ctx context.Context) {
+	ctx, span := w.tracer.Start(ctx, "feed.Worker.fanOut")
+	defer span.End()
+
+	for ctx.Err() == nil {
+		processed, err := w.repo.FanOut(ctx, w.batch)
+		if err != nil {
+			if ctx.Err() == nil {
+				span.RecordError(err)
+				log.Printf("failed fan out feed: %v", err)
+			}
+
+			return
+		}
+
+		if processed < w.batch {
+			return
+		}
+	}
+}
+
+func (w *Worker) Stop(ctx context.Context) error {
+	return w.loop.Stop(ctx)
+}
@@ -0,0 +1,187 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOk       = "ok"
+	StatusFailed   = "failed"
+	StatusDraining = "draining"
+)
+
+type (
+	// Check returns an error when the dependency it checks is not usable
+	Check func(ctx context.Context) error
+
+	Result struct {
+		Status     string `json:"status"`
+		Error      string `json:"error,omitempty"`
+		DurationMs int64  `json:"durationMs"`
+	}
+
+	Report struct {
+		Status string            `json:"status"`
+		Checks map[string]Result `json:"checks,omitempty"`
+	}
+
+	named struct {
+		name  string
+		check Check
+	}
+
+	// Checker answers the liveness and the readiness probes. The app is live as
+	// long as it serves requests, it is ready when every check passes and it is
+	// not shutting down.
+	Checker struct {
+		mu       sync.RWMutex
+		checks   []named
+		draining atomic.Bool
+		timeout  time.Duration
+	}
+)
+
+// NewChecker gives every check HEALTH_CHECK_TIMEOUT to pass, 2s by default
+func NewChecker() *Checker {
+	timeout, err := time.ParseDuration(os.Getenv("HEALTH_CHECK_TIMEOUT"))
+	if err != nil || timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+
+	return &Checker{
+		timeout: timeout,
+	}
+}
+
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks = append(c.checks, named{name: name, check: check})
+}
+
+// Drain fails the readiness from now on, so the app is taken out of the load
+// balancer before it stops serving.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Run runs the checks concurrently
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := c.checks
+	c.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		results = make([]Result, len(checks))
+		report  = Report{Status: StatusOk, Checks: make(map[string]Result, len(checks))}
+	)
+
+	for i, n := range checks {
+		wg.Add(1)
+
+		go func(i int, check Check) {
+			defer wg.Done()
+
+			started := time.Now()
+			err := check(ctx)
+
+			results[i] = Result{Status: StatusOk, DurationMs: time.Since(started).Milliseconds()}
+
+			if err != nil {
+				results[i].Status = StatusFailed
+				results[i].Error = err.Error()
+			}
+		}(i, n.check)
+	}
+
+	wg.Wait()
+
+	for i, n := range checks {
+		report.Checks[n.name] = results[i]
+
+		if results[i].Status != StatusOk {
+			report.Status = StatusFailed
+		}
+	}
+
+	if c.draining.Load() {
+		report.Status = StatusDraining
+	}
+
+	return report
+}
+
+// Live only tells that the app still serves requests, it checks no dependency
+// so an outage of the database does not get every instance restarted.
+func (c *Checker) Live(w http.ResponseWriter, r *http.Request) {
+	write(w, http.StatusOK, Report{Status: StatusOk})
+}
+
+// Ready runs the checks, the result of every check is only shown with the
+// verbose query parameter.
+func (c *Checker) Ready(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+
+	code := http.StatusOK
+	if report.Status != StatusOk {
+		code = http.StatusServiceUnavailable
+	}
+
+	if !r.URL.Query().Has("verbose") {
+		report.Checks = nil
+	}
+
+	write(w, code, report)
+}
+
+func write(w http.ResponseWriter, code int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+
+	json.NewEncoder(w).Encode(report) //nolint:errcheck
+}
+
+// Migrations fails while the schema of golang-migrate is dirty or older than
+// the version the app was built for.
+func Migrations(db *sql.DB, expected uint) Check {
+	return func(ctx context.Context) error {
+		var (
+			version uint
+			dirty   bool
+		)
+
+		err := db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations").Scan(&version, &dirty)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("no migration was run")
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if dirty {
+			return fmt.Errorf("migration %d is dirty", version)
+		}
+
+		if version < expected {
+			return fmt.Errorf("schema is at version %d, expected %d", version, expected)
+		}
+
+		return nil
+	}
+}
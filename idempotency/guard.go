@@ -0,0 +1,307 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rizface/quora/detach"
+	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/stdres"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	Header         = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+	maxKeyLength   = 255
+	recordTimeout  = 5 * time.Second
+)
+
+// Guard replays the recorded response of a request that is retried with the
+// same Idempotency-Key, so a retried POST does not create a duplicate.
+type Guard struct {
+	store  Store
+	tracer trace.Tracer
+	ttl    time.Duration
+	lease  time.Duration
+}
+
+// NewGuard keeps the responses for IDEMPOTENCY_TTL, 24h by default. A request
+// that is still running holds its key for IDEMPOTENCY_LEASE, so the key is
+// freed when the instance running it dies.
+func NewGuard(store Store, tracer trace.Tracer) *Guard {
+	ttl, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_TTL"))
+	if err != nil || ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+
+	lease, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_LEASE"))
+	if err != nil || lease <= 0 {
+		lease = time.Minute
+	}
+
+	return &Guard{
+		store:  store,
+		tracer: tracer,
+		ttl:    ttl,
+		lease:  lease,
+	}
+}
+
+// Handle is the middleware. The keys of every account are their own, so it
+// has to run after identifier.Identifier. Requests without the header are let
+// through, and so are requests with it when the store fails.
+func (g *Guard) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey := r.Header.Get(Header)
+		if idempotencyKey == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, span := g.tracer.Start(r.Context(), "idempotency.Guard.Handle")
+
+		if len(idempotencyKey) > maxKeyLength {
+			span.End()
+
+			stdres.Writer(w, r, stdres.Response{
+				Code: http.StatusBadRequest,
+				Info: fmt.Sprintf("%s is longer than %d characters", Header, maxKeyLength),
+			})
+
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			span.End()
+
+			stdres.Writer(w, r, stdres.Response{
+				Code: http.StatusBadRequest,
+				Info: "invalid request body",
+			})
+
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var (
+			key  = fmt.Sprintf("idempotency:%s:%s", identifier.ClientKey(r), idempotencyKey)
+			hash = requestHash(r, body)
+		)
+
+		span.SetAttributes(attribute.String("key", key))
+
+		record, claimed, err := g.store.Begin(ctx, key, Record{Hash: hash}, g.lease)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, fmt.Sprintf("error while claim idempotency key: %v", err))
+			span.End()
+
+			log.Printf("failed to claim idempotency key %s: %v", key, err)
+
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		span.SetAttributes(attribute.Bool("claimed", claimed))
+		span.End()
+
+		if !claimed {
+			g.replay(w, r, record, hash)
+			return
+		}
+
+		recorder := &recorder{ResponseWriter: w, status: http.StatusOK}
+
+		stop := g.keepLease(ctx, key, Record{Hash: hash})
+
+		// a panic is answered with a 500 by the recoverer, the key is freed
+		// so the request can be retried
+		defer func() {
+			stop()
+
+			if p := recover(); p != nil {
+				ctx, cancel := detach.WithTimeout(ctx, recordTimeout)
+				defer cancel()
+
+				if err := g.store.Release(ctx, key); err != nil {
+					log.Printf("failed to release idempotency key %s: %v", key, err)
+				}
+
+				panic(p)
+			}
+		}()
+
+		next.ServeHTTP(recorder, r)
+
+		stop()
+
+		// the client of a slow request may be gone by now, its response is
+		// still recorded for the retry
+		ctx, cancel := detach.WithTimeout(ctx, recordTimeout)
+		defer cancel()
+
+		g.finish(ctx, key, hash, recorder)
+	})
+}
+
+// keepLease extends the lease of the key every half lease until stop is called,
+// so the key of a request that runs longer than the lease is not claimed by a
+// retry. Calling stop again does nothing.
+func (g *Guard) keepLease(ctx context.Context, key string, record Record) (stop func()) {
+	var (
+		done = make(chan struct{})
+		wg   sync.WaitGroup
+	)
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(g.lease / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				ctx, cancel := detach.WithTimeout(ctx, recordTimeout)
+
+				if err := g.store.Extend(ctx, key, record, g.lease); err != nil {
+					log.Printf("failed to extend the lease of idempotency key %s: %v", key, err)
+				}
+
+				cancel()
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// replay writes the recorded response, the requests that did not finish yet or
+// that were sent with another body are refused.
+func (g *Guard) replay(w http.ResponseWriter, r *http.Request, record Record, hash string) {
+	if record.Hash != hash {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnprocessableEntity,
+			Info: fmt.Sprintf("%s was used for another request", Header),
+		})
+
+		return
+	}
+
+	if !record.Done {
+		w.Header().Set("Retry-After", "1")
+
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusConflict,
+			Info: fmt.Sprintf("a request with the same %s is in progress", Header),
+		})
+
+		return
+	}
+
+	for k, v := range record.Header {
+		w.Header().Set(k, v)
+	}
+
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(record.Status)
+	w.Write(record.Body) //nolint:errcheck
+}
+
+// finish records the response. Server errors and rate limited requests may
+// succeed when they are retried, their key is freed instead.
+func (g *Guard) finish(ctx context.Context, key, hash string, recorder *recorder) {
+	ctx, span := g.tracer.Start(ctx, "idempotency.Guard.finish")
+	defer span.End()
+
+	var err error
+
+	if recorder.status >= http.StatusInternalServerError || recorder.status == http.StatusTooManyRequests {
+		err = g.store.Release(ctx, key)
+	} else {
+		err = g.store.Complete(ctx, key, Record{
+			Hash:   hash,
+			Done:   true,
+			Status: recorder.status,
+			Header: recorder.header,
+			Body:   recorder.body.Bytes(),
+		}, g.ttl)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while record response: %v", err))
+
+		log.Printf("failed to record the response of idempotency key %s: %v", key, err)
+	}
+}
+
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+
+	fmt.Fprintf(h, "%s %s\n", r.Method, r.URL.RequestURI())
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder copies the response while it is written. Only the Content-Type is
+// recorded with it, the other headers describe the original request.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	header      map[string]string
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+
+	r.wroteHeader = true
+	r.status = status
+
+	if contentType := r.Header().Get("Content-Type"); contentType != "" {
+		r.header = map[string]string{"Content-Type": contentType}
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	r.body.Write(b)
+
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,81 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	record    Record
+	expiresAt time.Time
+}
+
+// MemoryStore keeps the records in the process, it is meant for a single
+// instance and for running without Redis. Expired records are swept at most
+// once a minute.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]entry
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: map[string]entry{},
+	}
+}
+
+func (m *MemoryStore) Begin(ctx context.Context, key string, record Record, lease time.Duration) (Record, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(m.lastSweep) >= time.Minute {
+		for key, e := range m.entries {
+			if !now.Before(e.expiresAt) {
+				delete(m.entries, key)
+			}
+		}
+
+		m.lastSweep = now
+	}
+
+	if e, ok := m.entries[key]; ok && now.Before(e.expiresAt) {
+		return e.record, false, nil
+	}
+
+	m.entries[key] = entry{record: record, expiresAt: now.Add(lease)}
+
+	return record, true, nil
+}
+
+func (m *MemoryStore) Extend(ctx context.Context, key string, record Record, lease time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.entries[key]; ok && !e.record.Done && e.record.Hash == record.Hash {
+		m.entries[key] = entry{record: e.record, expiresAt: time.Now().Add(lease)}
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) Complete(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = entry{record: record, expiresAt: time.Now().Add(ttl)}
+
+	return nil
+}
+
+func (m *MemoryStore) Release(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{
+		client: client,
+	}
+}
+
+// Begin claims the key with SET NX, so concurrent retries on several instances
+// run the request once.
+func (r *RedisStore) Begin(ctx context.Context, key string, record Record, lease time.Duration) (Record, bool, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	// the claim may expire between SET NX and GET
+	for i := 0; i < 3; i++ {
+		claimed, err := r.client.SetNX(ctx, key, data, lease).Result()
+		if err != nil {
+			return Record{}, false, err
+		}
+
+		if claimed {
+			return record, true, nil
+		}
+
+		existing, err := r.client.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+
+		if err != nil {
+			return Record{}, false, err
+		}
+
+		var found Record
+
+		return found, false, json.Unmarshal(existing, &found)
+	}
+
+	return Record{}, false, errors.New("failed to claim idempotency key")
+}
+
+// extendScript renews the lease only while the key holds the claim, so it never
+// shortens the ttl of a recorded response.
+var extendScript = redis.NewScript(`
+	if redis.call("GET", KEYS[1]) == ARGV[1] then
+		return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	end
+
+	return 0
+`)
+
+func (r *RedisStore) Extend(ctx context.Context, key string, record Record, lease time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return extendScript.Run(ctx, r.client, []string{key}, data, lease.Milliseconds()).Err()
+}
+
+func (r *RedisStore) Complete(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Set(ctx, key, data, ttl).Err()
+}
+
+func (r *RedisStore) Release(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key).Err()
+}
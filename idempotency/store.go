@@ -0,0 +1,29 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// Record is what is kept for a key. A record without a response belongs to
+	// a request that is still running.
+	Record struct {
+		Hash   string            `json:"hash"`
+		Done   bool              `json:"done"`
+		Status int               `json:"status"`
+		Header map[string]string `json:"header"`
+		Body   []byte            `json:"body"`
+	}
+
+	// Store keeps the records of the keys. Begin claims the key for the request
+	// with a record without a response that expires after the lease, when the
+	// key is already claimed it returns the existing record and false. Extend
+	// renews the lease as long as the key still holds the claimed record.
+	Store interface {
+		Begin(ctx context.Context, key string, record Record, lease time.Duration) (Record, bool, error)
+		Extend(ctx context.Context, key string, record Record, lease time.Duration) error
+		Complete(ctx context.Context, key string, record Record, ttl time.Duration) error
+		Release(ctx context.Context, key string) error
+	}
+)
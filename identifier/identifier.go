@@ -3,11 +3,14 @@ package identifier
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/rizface/quora/requestlog"
 	"github.com/rizface/quora/stdres"
 )
 
@@ -62,7 +65,7 @@ func Identifier(next http.Handler) http.Handler {
 		splittedToken := strings.Split(r.Header.Get("Authorization"), " ")
 
 		if err := validateTokenForm(splittedToken); err != nil {
-			stdres.Writer(w, stdres.Response{
+			stdres.Writer(w, r, stdres.Response{
 				Code: http.StatusUnauthorized,
 				Info: err.Error(),
 			})
@@ -75,7 +78,7 @@ func Identifier(next http.Handler) http.Handler {
 			claim, err = getClaim(token)
 		)
 		if err != nil {
-			stdres.Writer(w, stdres.Response{
+			stdres.Writer(w, r, stdres.Response{
 				Code: http.StatusUnauthorized,
 				Info: err.Error(),
 			})
@@ -83,6 +86,36 @@ func Identifier(next http.Handler) http.Handler {
 			return
 		}
 
+		requestlog.SetAccountId(r.Context(), claim.AccountId)
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ClaimKey, claim)))
+	})
+}
+
+// QueryIdentifier works like Identifier but also accepts the token in the
+// "token" query parameter, for clients like EventSource that can not send
+// request headers.
+func QueryIdentifier(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			Identifier(next).ServeHTTP(w, r)
+
+			return
+		}
+
+		claim, err := getClaim(token)
+		if err != nil {
+			stdres.Writer(w, r, stdres.Response{
+				Code: http.StatusUnauthorized,
+				Info: err.Error(),
+			})
+
+			return
+		}
+
+		requestlog.SetAccountId(r.Context(), claim.AccountId)
+
 		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ClaimKey, claim)))
 	})
 }
@@ -95,3 +128,26 @@ func GetFromContext(ctx context.Context) (*Claim, error) {
 
 	return claim.(*Claim), nil
 }
+
+// ClientIP is the peer of the connection. Put middleware.RealIP in front of the
+// router only when it runs behind a proxy that sets the forwarded headers,
+// otherwise clients could pick their own IP.
+func ClientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return ip
+}
+
+// ClientKey is the account of the request, or its IP when it is anonymous. It
+// only sees the account when it runs after Identifier.
+func ClientKey(r *http.Request) string {
+	claim, err := GetFromContext(r.Context())
+	if err == nil && claim.AccountId != "" {
+		return fmt.Sprintf("account:%s", claim.AccountId)
+	}
+
+	return fmt.Sprintf("ip:%s", ClientIP(r))
+}
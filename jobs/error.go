@@ -0,0 +1,8 @@
+package jobs
+
+import "errors"
+
+var (
+	ErrJobNotFound  = errors.New("failed job not found")
+	ErrNotModerator = errors.New("only moderators can manage jobs")
+)
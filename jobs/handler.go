@@ -0,0 +1,151 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/jobs/value"
+	"github.com/rizface/quora/stdres"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Handler struct {
+	tracer trace.Tracer
+	svc    *Service
+}
+
+func NewHandler(svc *Service, tracer trace.Tracer) *Handler {
+	return &Handler{
+		tracer: tracer,
+		svc:    svc,
+	}
+}
+
+func (h *Handler) GetJobs(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "jobs.Handler.GetJobs")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	query, err := value.NewJobQuery(r.URL.Query())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "invalid query parameter",
+		})
+
+		return
+	}
+
+	jobs, err := h.svc.GetJobs(ctx, Input{
+		Identity: *identity,
+		JobQuery: query,
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrNotModerator) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusForbidden,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while get jobs: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{
+			"docs": jobs,
+		},
+	})
+}
+
+func (h *Handler) RetryJob(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "jobs.Handler.RetryJob")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	err = h.svc.RetryJob(ctx, Input{
+		Identity: *identity,
+		JobId:    chi.URLParam(r, "id"),
+	})
+	if errors.Is(err, ErrNotModerator) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusForbidden,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrJobNotFound) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusNotFound,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while retry job: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+	})
+}
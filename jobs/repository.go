@@ -0,0 +1,251 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rizface/quora/jobs/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// execer is satisfied by *sql.DB and *sql.Tx so a job can be enqueued with
+// the transaction of the change that needs it.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type Repository struct {
+	db     *sql.DB
+	tracer trace.Tracer
+}
+
+func NewRepository(db *sql.DB, tracer trace.Tracer) *Repository {
+	return &Repository{
+		db:     db,
+		tracer: tracer,
+	}
+}
+
+func (r *Repository) Enqueue(ctx context.Context, db execer, job value.Job, uniqueKey string) error {
+	ctx, span := r.tracer.Start(ctx, "jobs.Repository.Enqueue")
+	defer span.End()
+
+	command := `
+		INSERT INTO jobs (queue, kind, payload, max_attempts, run_at, unique_key) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+		ON CONFLICT (unique_key) WHERE unique_key IS NOT NULL DO NOTHING
+	`
+
+	_, err := db.ExecContext(ctx, command, job.Queue, job.Kind, job.Payload, job.MaxAttempts, job.RunAt, uniqueKey)
+
+	return err
+}
+
+// Claim marks the due jobs of the queue as running until lockedUntil. Running
+// jobs whose lease expired were abandoned by a crashed runner and are claimed
+// again.
+func (r *Repository) Claim(ctx context.Context, queue string, kinds []string, limit int, lockedUntil time.Time) ([]value.Job, error) {
+	ctx, span := r.tracer.Start(ctx, "jobs.Repository.Claim")
+	defer span.End()
+
+	var (
+		jobs    = []value.Job{}
+		command = `
+			UPDATE jobs SET status = $1, attempts = attempts + 1, locked_until = $2
+			WHERE id IN (
+				SELECT id FROM jobs
+				WHERE queue = $3 AND kind = ANY($4) AND run_at <= now()
+				AND (status = $5 OR (status = $1 AND locked_until < now()))
+				ORDER BY run_at
+				LIMIT $6
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, queue, kind, payload, status, attempts, max_attempts, run_at, created_at
+		`
+	)
+
+	rows, err := r.db.QueryContext(ctx, command,
+		value.StatusRunning, lockedUntil, queue, pq.Array(kinds), value.StatusPending, limit,
+	)
+	if err != nil {
+		return jobs, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var job value.Job
+
+		err := rows.Scan(
+			&job.Id, &job.Queue, &job.Kind, &job.Payload, &job.Status,
+			&job.Attempts, &job.MaxAttempts, &job.RunAt, &job.CreatedAt,
+		)
+		if err != nil {
+			return jobs, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func (r *Repository) Complete(ctx context.Context, id string) error {
+	ctx, span := r.tracer.Start(ctx, "jobs.Repository.Complete")
+	defer span.End()
+
+	command := `
+		UPDATE jobs SET status = $1, locked_until = NULL, last_error = NULL, finished_at = now() WHERE id = $2
+	`
+
+	_, err := r.db.ExecContext(ctx, command, value.StatusSucceeded, id)
+
+	return err
+}
+
+// Fail records the error of the attempt, the job is either pending again at
+// runAt or failed for good.
+func (r *Repository) Fail(ctx context.Context, id string, cause error, status string, runAt time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "jobs.Repository.Fail")
+	defer span.End()
+
+	command := `
+		UPDATE jobs SET
+			status = $1,
+			run_at = $2,
+			last_error = $3,
+			locked_until = NULL,
+			finished_at = CASE WHEN $1 = $4 THEN now() END
+		WHERE id = $5
+	`
+
+	_, err := r.db.ExecContext(ctx, command, status, runAt, cause.Error(), value.StatusFailed, id)
+
+	return err
+}
+
+// Release hands a job interrupted by a stopping runner back to the queue, the
+// interrupted attempt does not count.
+func (r *Repository) Release(ctx context.Context, id string) error {
+	ctx, span := r.tracer.Start(ctx, "jobs.Repository.Release")
+	defer span.End()
+
+	command := `
+		UPDATE jobs SET status = $1, attempts = attempts - 1, locked_until = NULL WHERE id = $2
+	`
+
+	_, err := r.db.ExecContext(ctx, command, value.StatusPending, id)
+
+	return err
+}
+
+func (r *Repository) GetList(ctx context.Context, q value.JobQuery) ([]value.Job, error) {
+	ctx, span := r.tracer.Start(ctx, "jobs.Repository.GetList")
+	defer span.End()
+
+	var (
+		jobs  = []value.Job{}
+		args  = []interface{}{q.Status, q.Limit, q.Skip}
+		where = "status = $1"
+	)
+
+	if q.Queue != "" {
+		args = append(args, q.Queue)
+		where += fmt.Sprintf(" AND queue = $%d", len(args))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, queue, kind, payload, status, attempts, max_attempts, run_at, last_error, finished_at, created_at
+		FROM jobs
+		WHERE %s
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, where)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return jobs, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var job value.Job
+
+		err := rows.Scan(
+			&job.Id, &job.Queue, &job.Kind, &job.Payload, &job.Status, &job.Attempts,
+			&job.MaxAttempts, &job.RunAt, &job.LastError, &job.FinishedAt, &job.CreatedAt,
+		)
+		if err != nil {
+			return jobs, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// Retry schedules a failed job with a fresh set of attempts.
+func (r *Repository) Retry(ctx context.Context, id string) error {
+	ctx, span := r.tracer.Start(ctx, "jobs.Repository.Retry")
+	defer span.End()
+
+	command := `
+		UPDATE jobs SET status = $1, attempts = 0, run_at = now(), finished_at = NULL WHERE id = $2 AND status = $3
+	`
+
+	result, err := r.db.ExecContext(ctx, command, value.StatusPending, id, value.StatusFailed)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrJobNotFound
+	}
+
+	return nil
+}
+
+// Purge deletes the succeeded jobs finished before the given time, failed
+// jobs are kept until they are inspected.
+func (r *Repository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "jobs.Repository.Purge")
+	defer span.End()
+
+	command := `
+		DELETE FROM jobs WHERE status = $1 AND finished_at < $2
+	`
+
+	result, err := r.db.ExecContext(ctx, command, value.StatusSucceeded, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *Repository) IsModerator(ctx context.Context, accountId string) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "jobs.Repository.IsModerator")
+	defer span.End()
+
+	var (
+		isModerator bool
+		query       = `
+			SELECT role IN ('moderator', 'admin') FROM accounts WHERE id = $1
+		`
+	)
+
+	err := r.db.QueryRowContext(ctx, query, accountId).Scan(&isModerator)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	return isModerator, err
+}
@@ -0,0 +1,381 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rizface/quora/jobs/value"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Job is the payload of a job, the kind routes it to its handler
+	Job interface {
+		Kind() string
+	}
+
+	schedule struct {
+		interval time.Duration
+		job      Job
+		opt      value.Options
+	}
+
+	// Runner runs the jobs of the jobs table. Every instance claims the due
+	// jobs with SKIP LOCKED, a job is run by one instance at a time and a job
+	// abandoned by a crashed instance is run again once its lease expires.
+	Runner struct {
+		repo      *Repository
+		tracer    trace.Tracer
+		interval  time.Duration
+		baseDelay time.Duration
+		maxDelay  time.Duration
+		lease     time.Duration
+
+		mu        sync.RWMutex
+		handlers  map[string]func(ctx context.Context, payload json.RawMessage) error
+		queues    map[string]int
+		schedules []schedule
+
+		cancel     context.CancelFunc
+		wg         sync.WaitGroup
+		jobsCtx    context.Context
+		cancelJobs context.CancelFunc
+		running    sync.WaitGroup
+	}
+)
+
+func NewRunner(repo *Repository, tracer trace.Tracer) *Runner {
+	interval, err := time.ParseDuration(os.Getenv("JOBS_POLL_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = time.Second
+	}
+
+	baseDelay, err := time.ParseDuration(os.Getenv("JOBS_RETRY_BASE_DELAY"))
+	if err != nil || baseDelay <= 0 {
+		baseDelay = 10 * time.Second
+	}
+
+	lease, err := time.ParseDuration(os.Getenv("JOBS_TIMEOUT"))
+	if err != nil || lease <= 0 {
+		lease = 5 * time.Minute
+	}
+
+	concurrency, err := strconv.Atoi(os.Getenv("JOBS_CONCURRENCY"))
+	if err != nil || concurrency <= 0 {
+		concurrency = 4
+	}
+
+	retention, err := time.ParseDuration(os.Getenv("JOBS_RETENTION"))
+	if err != nil || retention <= 0 {
+		retention = 7 * 24 * time.Hour
+	}
+
+	r := &Runner{
+		repo:      repo,
+		tracer:    tracer,
+		interval:  interval,
+		baseDelay: baseDelay,
+		maxDelay:  6 * time.Hour,
+		lease:     lease,
+		handlers:  map[string]func(ctx context.Context, payload json.RawMessage) error{},
+		queues:    map[string]int{value.DefaultQueue: concurrency},
+	}
+
+	Register(r, func(ctx context.Context, job purgeJob) error {
+		_, err := repo.Purge(ctx, time.Now().Add(-retention))
+		return err
+	})
+	r.Every(time.Hour, purgeJob{}, value.Options{})
+
+	return r
+}
+
+// purgeJob deletes the succeeded jobs older than the retention
+type purgeJob struct{}
+
+func (purgeJob) Kind() string { return "jobs.purge" }
+
+// Register sets the handler of the jobs of type T. A job may run more than
+// once when its runner crashes, handlers must tolerate it.
+func Register[T Job](r *Runner, handle func(ctx context.Context, job T) error) {
+	var zero T
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.handlers[zero.Kind()] = func(ctx context.Context, payload json.RawMessage) error {
+		var job T
+
+		if err := json.Unmarshal(payload, &job); err != nil {
+			return fmt.Errorf("failed decode job %s: %w", zero.Kind(), err)
+		}
+
+		return handle(ctx, job)
+	}
+}
+
+// Queue sets how many jobs of the queue an instance runs at the same time.
+// Jobs of a queue that is not configured are not run by this instance.
+func (r *Runner) Queue(name string, concurrency int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.queues[name] = concurrency
+}
+
+// Every enqueues the job once per interval, the runs are keyed by their time
+// so every instance can schedule the job and it still runs once.
+func (r *Runner) Every(interval time.Duration, job Job, opt value.Options) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.schedules = append(r.schedules, schedule{
+		interval: interval,
+		job:      job,
+		opt:      opt,
+	})
+}
+
+func (r *Runner) Enqueue(ctx context.Context, job Job, opt value.Options) error {
+	return r.enqueue(ctx, r.repo.db, job, opt)
+}
+
+// EnqueueTx enqueues the job with the transaction, the job only runs if the
+// transaction is committed.
+func (r *Runner) EnqueueTx(ctx context.Context, tx *sql.Tx, job Job, opt value.Options) error {
+	return r.enqueue(ctx, tx, job, opt)
+}
+
+func (r *Runner) enqueue(ctx context.Context, db execer, job Job, opt value.Options) error {
+	ctx, span := r.tracer.Start(ctx, "jobs.Runner.Enqueue")
+	defer span.End()
+
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	j := value.NewJob(job.Kind(), payload, opt)
+
+	span.SetAttributes(
+		attribute.String("job.kind", j.Kind),
+		attribute.String("job.queue", j.Queue),
+	)
+
+	return r.repo.Enqueue(ctx, db, j, opt.UniqueKey)
+}
+
+func (r *Runner) kinds() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	kinds := make([]string, 0, len(r.handlers))
+	for kind := range r.handlers {
+		kinds = append(kinds, kind)
+	}
+
+	sort.Strings(kinds)
+
+	return kinds
+}
+
+func (r *Runner) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	// jobs outlive the pollers so Stop can let them finish
+	r.jobsCtx, r.cancelJobs = context.WithCancel(context.Background())
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for queue, concurrency := range r.queues {
+		r.wg.Add(1)
+		go r.poll(ctx, queue, concurrency)
+	}
+
+	for _, s := range r.schedules {
+		r.wg.Add(1)
+		go r.schedule(ctx, s)
+	}
+}
+
+// Stop stops claiming jobs and waits for the running jobs to finish. Jobs
+// still running when ctx is done are canceled and handed back to the queue.
+func (r *Runner) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+
+	r.cancel()
+	r.wg.Wait()
+
+	done := make(chan struct{})
+
+	go func() {
+		r.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		r.cancelJobs()
+		return nil
+	case <-ctx.Done():
+		r.cancelJobs()
+		<-done
+
+		return ctx.Err()
+	}
+}
+
+func (r *Runner) poll(ctx context.Context, queue string, concurrency int) {
+	defer r.wg.Done()
+
+	var (
+		ticker = time.NewTicker(r.interval)
+		slots  = make(chan struct{}, concurrency)
+	)
+	defer ticker.Stop()
+
+	for {
+		free := concurrency - len(slots)
+
+		if free > 0 {
+			jobs, err := r.repo.Claim(ctx, queue, r.kinds(), free, time.Now().Add(r.lease))
+			if err != nil && ctx.Err() == nil {
+				log.Printf("failed claim jobs of %s: %v", queue, err)
+			}
+
+			for _, job := range jobs {
+				slots <- struct{}{}
+				r.running.Add(1)
+
+				go func(job value.Job) {
+					defer func() {
+						<-slots
+						r.running.Done()
+					}()
+
+					r.run(job)
+				}(job)
+			}
+
+			// more jobs may be due
+			if len(jobs) == free {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) schedule(ctx context.Context, s schedule) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		var (
+			runAt = time.Now().Truncate(s.interval)
+			opt   = s.opt
+		)
+
+		opt.RunAt = runAt
+		opt.UniqueKey = fmt.Sprintf("%s:%d", s.job.Kind(), runAt.Unix())
+
+		if err := r.Enqueue(ctx, s.job, opt); err != nil && ctx.Err() == nil {
+			log.Printf("failed schedule job %s: %v", s.job.Kind(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) run(job value.Job) {
+	ctx, cancel := context.WithTimeout(r.jobsCtx, r.lease)
+	defer cancel()
+
+	ctx, span := r.tracer.Start(ctx, "jobs.Runner.run")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("job.id", job.Id),
+		attribute.String("job.kind", job.Kind),
+		attribute.String("job.queue", job.Queue),
+		attribute.Int("job.attempt", job.Attempts),
+	)
+
+	err := r.handle(ctx, job)
+
+	// the bookkeeping must happen even when the job timed out
+	bookkeeping := trace.ContextWithSpan(context.Background(), span)
+
+	switch {
+	case err == nil:
+		err = r.repo.Complete(bookkeeping, job.Id)
+	case r.jobsCtx.Err() != nil:
+		log.Printf("job %s %s interrupted by shutdown: %v", job.Kind, job.Id, err)
+		err = r.repo.Release(bookkeeping, job.Id)
+	default:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while run job %s: %v", job.Kind, err))
+		log.Printf("failed run job %s %s: %v", job.Kind, job.Id, err)
+
+		var (
+			status = value.StatusPending
+			runAt  = time.Now().Add(value.Backoff(job.Attempts, r.baseDelay, r.maxDelay))
+		)
+
+		if job.IsExhausted() {
+			status, runAt = value.StatusFailed, job.RunAt
+		}
+
+		err = r.repo.Fail(bookkeeping, job.Id, err, status, runAt)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		log.Printf("failed save result of job %s: %v", job.Id, err)
+	}
+}
+
+// handle turns a panic of the handler into an error so it is retried like
+// any other failure.
+func (r *Runner) handle(ctx context.Context, job value.Job) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job panicked: %v", p)
+		}
+	}()
+
+	r.mu.RLock()
+	handler, ok := r.handlers[job.Kind]
+	r.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("no handler for job %s", job.Kind)
+	}
+
+	return handler(ctx, job.Payload)
+}
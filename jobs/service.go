@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/jobs/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	Service struct {
+		tracer trace.Tracer
+		repo   *Repository
+	}
+
+	Input struct {
+		Identity identifier.Claim
+		JobId    string
+		JobQuery value.JobQuery
+	}
+)
+
+func NewService(repo *Repository, tracer trace.Tracer) *Service {
+	return &Service{
+		repo:   repo,
+		tracer: tracer,
+	}
+}
+
+func (s *Service) ensureModerator(ctx context.Context, identity identifier.Claim) error {
+	isModerator, err := s.repo.IsModerator(ctx, identity.AccountId)
+	if err != nil {
+		return err
+	}
+
+	if !isModerator {
+		return ErrNotModerator
+	}
+
+	return nil
+}
+
+func (s *Service) GetJobs(ctx context.Context, input Input) ([]value.Job, error) {
+	ctx, span := s.tracer.Start(ctx, "jobs.Service.GetJobs")
+	defer span.End()
+
+	if err := value.ValidateJobQuery(input.JobQuery); err != nil {
+		return []value.Job{}, err
+	}
+
+	if err := s.ensureModerator(ctx, input.Identity); err != nil {
+		return []value.Job{}, err
+	}
+
+	return s.repo.GetList(ctx, input.JobQuery)
+}
+
+func (s *Service) RetryJob(ctx context.Context, input Input) error {
+	ctx, span := s.tracer.Start(ctx, "jobs.Service.RetryJob")
+	defer span.End()
+
+	if err := s.ensureModerator(ctx, input.Identity); err != nil {
+		return err
+	}
+
+	if err := value.ValidateId(input.JobId); err != nil {
+		return ErrJobNotFound
+	}
+
+	return s.repo.Retry(ctx, input.JobId)
+}
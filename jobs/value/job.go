@@ -0,0 +1,141 @@
+package value
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/rizface/quora/nuller"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	// StatusFailed is set once every attempt failed, only a manual retry runs
+	// the job again
+	StatusFailed = "failed"
+
+	DefaultQueue       = "default"
+	DefaultMaxAttempts = 5
+)
+
+type (
+	// Options of an enqueued job, the zero value runs the job on the default
+	// queue as soon as possible.
+	Options struct {
+		Queue       string
+		RunAt       time.Time
+		MaxAttempts int
+		UniqueKey   string
+	}
+
+	Job struct {
+		Id          string            `json:"id"`
+		Queue       string            `json:"queue"`
+		Kind        string            `json:"kind"`
+		Payload     json.RawMessage   `json:"payload"`
+		Status      string            `json:"status"`
+		Attempts    int               `json:"attempts"`
+		MaxAttempts int               `json:"maxAttempts"`
+		RunAt       time.Time         `json:"runAt"`
+		LastError   nuller.NullString `json:"lastError"`
+		FinishedAt  *time.Time        `json:"finishedAt"`
+		CreatedAt   time.Time         `json:"createdAt"`
+	}
+
+	JobQuery struct {
+		Status string `json:"status"`
+		Queue  string `json:"queue"`
+		Limit  int    `json:"limit"`
+		Skip   int    `json:"skip"`
+	}
+)
+
+func NewJob(kind string, payload json.RawMessage, opt Options) Job {
+	job := Job{
+		Queue:       opt.Queue,
+		Kind:        kind,
+		Payload:     payload,
+		Status:      StatusPending,
+		MaxAttempts: opt.MaxAttempts,
+		RunAt:       opt.RunAt,
+	}
+
+	if job.Queue == "" {
+		job.Queue = DefaultQueue
+	}
+
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = DefaultMaxAttempts
+	}
+
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+
+	return job
+}
+
+// IsExhausted reports whether the job failed its last attempt.
+func (j Job) IsExhausted() bool {
+	return j.Attempts >= j.MaxAttempts
+}
+
+// Backoff doubles the delay on every failed attempt.
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	delay := base << (attempts - 1)
+	if attempts > 30 || delay <= 0 || delay > max {
+		return max
+	}
+
+	return delay
+}
+
+func NewJobQuery(url url.Values) (JobQuery, error) {
+	q := JobQuery{
+		Status: StatusFailed,
+		Queue:  url.Get("queue"),
+		Limit:  20,
+		Skip:   0,
+	}
+
+	if url.Has("status") && url.Get("status") != "" {
+		q.Status = url.Get("status")
+	}
+
+	if url.Has("limit") && url.Get("limit") != "" {
+		limit, err := strconv.Atoi(url.Get("limit"))
+		if err != nil {
+			return JobQuery{}, err
+		}
+
+		q.Limit = limit
+	}
+
+	if url.Has("skip") && url.Get("skip") != "" {
+		skip, err := strconv.Atoi(url.Get("skip"))
+		if err != nil {
+			return JobQuery{}, err
+		}
+
+		q.Skip = skip
+	}
+
+	return q, nil
+}
+
+func ValidateJobQuery(q JobQuery) error {
+	return validation.Errors{
+		"limit":  validation.Validate(q.Limit, validation.Min(1), validation.Max(100)),
+		"skip":   validation.Validate(q.Skip, validation.Min(0)),
+		"status": validation.Validate(q.Status, validation.In(StatusPending, StatusRunning, StatusSucceeded, StatusFailed)),
+	}.Filter()
+}
+
+func ValidateId(id string) error {
+	return validation.Validate(id, validation.Required, is.UUID)
+}
@@ -0,0 +1,285 @@
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/notification/value"
+	"github.com/rizface/quora/stdres"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Handler struct {
+	tracer trace.Tracer
+	svc    *Service
+}
+
+func NewHandler(svc *Service, tracer trace.Tracer) *Handler {
+	return &Handler{
+		tracer: tracer,
+		svc:    svc,
+	}
+}
+
+func (h *Handler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "notification.Handler.GetNotifications")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	query, err := value.NewNotificationQuery(r.URL.Query())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "invalid query parameter",
+		})
+
+		return
+	}
+
+	inbox, err := h.svc.GetNotifications(ctx, Input{
+		Identity:          *identity,
+		NotificationQuery: query,
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while get notifications: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{
+			"docs":   inbox.Notifications,
+			"unread": inbox.Unread,
+		},
+	})
+}
+
+func (h *Handler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "notification.Handler.MarkRead")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	err = h.svc.MarkRead(ctx, Input{
+		Identity:       *identity,
+		NotificationId: chi.URLParam(r, "id"),
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrNotificationNotFound) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusNotFound,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while mark notification as read: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+	})
+}
+
+func (h *Handler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "notification.Handler.MarkAllRead")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	updated, err := h.svc.MarkAllRead(ctx, Input{
+		Identity: *identity,
+	})
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while mark all notifications as read: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{
+			"updated": updated,
+		},
+	})
+}
+
+func (h *Handler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "notification.Handler.GetPreferences")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	preferences, err := h.svc.GetPreferences(ctx, Input{
+		Identity: *identity,
+	})
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while get notification preferences: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{
+			"docs": preferences,
+		},
+	})
+}
+
+func (h *Handler) UpdatePreference(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "notification.Handler.UpdatePreference")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	var payload value.Preference
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	preference, err := h.svc.UpdatePreference(ctx, Input{
+		Identity:   *identity,
+		Preference: payload,
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while update notification preference: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{
+			"doc": preference,
+		},
+	})
+}
@@ -0,0 +1,44 @@
+package notification
+
+import (
+	"database/sql"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rizface/quora/events"
+	"github.com/rizface/quora/identifier"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Feature struct {
+	handler *Handler
+	r       *chi.Mux
+}
+
+func NewFeature(r *chi.Mux, db *sql.DB, tracer trace.Tracer, bus *events.Bus) *Feature {
+	var (
+		repo    = NewRepository(db, tracer)
+		svc     = NewService(repo, tracer)
+		handler = NewHandler(svc, tracer)
+	)
+
+	NewSubscriber(repo, tracer).Register(bus)
+
+	return &Feature{
+		handler: handler,
+		r:       r,
+	}
+}
+
+func (f *Feature) RegisterRoutes() {
+	f.r.Group(func(r chi.Router) {
+		r.Use(identifier.Identifier)
+
+		r.Route("/notifications", func(r chi.Router) {
+			r.Get("/", f.handler.GetNotifications)
+			r.Patch("/read", f.handler.MarkAllRead)
+			r.Patch("/{id}/read", f.handler.MarkRead)
+			r.Get("/preferences", f.handler.GetPreferences)
+			r.Put("/preferences", f.handler.UpdatePreference)
+		})
+	})
+}
@@ -0,0 +1,254 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/rizface/quora/notification/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Repository struct {
+	db     *sql.DB
+	tracer trace.Tracer
+}
+
+func NewRepository(db *sql.DB, tracer trace.Tracer) *Repository {
+	return &Repository{
+		db:     db,
+		tracer: tracer,
+	}
+}
+
+// Create sends a copy of the notification to every recipient except the actor, skipping
+// recipients that turned the notification type off.
+func (r *Repository) Create(ctx context.Context, tx *sql.Tx, n value.Notification, recipients []string) error {
+	ctx, span := r.tracer.Start(ctx, "notification.Repository.Create")
+	defer span.End()
+
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	command := `
+		INSERT INTO notifications (id, recipient_id, "type", actor_id, question_id, answer_id, data, created_at)
+		SELECT gen_random_uuid(), r.recipient_id, $1, $2, $3, $4, $5, $6
+		FROM unnest($7::uuid[]) AS r(recipient_id)
+		WHERE r.recipient_id IS DISTINCT FROM $2::uuid
+		AND NOT EXISTS (
+			SELECT 1 FROM notification_preferences p
+			WHERE p.account_id = r.recipient_id AND p."type" = $1 AND NOT p.enabled
+		)
+	`
+
+	_, err := tx.ExecContext(ctx, command,
+		n.Type, n.ActorId, n.QuestionId, n.AnswerId, []byte(n.Data), n.CreatedAt, pq.Array(recipients),
+	)
+
+	return err
+}
+
+func (r *Repository) GetQuestionFollowers(ctx context.Context, questionId string) ([]string, error) {
+	ctx, span := r.tracer.Start(ctx, "notification.Repository.GetQuestionFollowers")
+	defer span.End()
+
+	query := `
+		SELECT account_id FROM question_followers WHERE question_id = $1
+	`
+
+	return r.selectIds(ctx, query, questionId)
+}
+
+func (r *Repository) GetAccountsByUsernames(ctx context.Context, usernames []string) ([]string, error) {
+	ctx, span := r.tracer.Start(ctx, "notification.Repository.GetAccountsByUsernames")
+	defer span.End()
+
+	if len(usernames) == 0 {
+		return []string{}, nil
+	}
+
+	query := `
+		SELECT id FROM accounts WHERE LOWER(username) = ANY($1::text[])
+	`
+
+	return r.selectIds(ctx, query, pq.Array(usernames))
+}
+
+func (r *Repository) selectIds(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	ids := []string{}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return ids, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+
+		if err := rows.Scan(&id); err != nil {
+			return []string{}, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *Repository) GetList(ctx context.Context, recipientId string, q value.NotificationQuery) ([]value.Notification, error) {
+	ctx, span := r.tracer.Start(ctx, "notification.Repository.GetList")
+	defer span.End()
+
+	var (
+		notifications = []value.Notification{}
+		query         = `
+			SELECT id, recipient_id, "type", actor_id, question_id, answer_id, data, read_at, created_at
+			FROM notifications
+			WHERE recipient_id = $1 AND (NOT $2 OR read_at IS NULL)
+			ORDER BY created_at DESC, id
+			LIMIT $3 OFFSET $4
+		`
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, recipientId, q.UnreadOnly, q.Limit, q.Skip)
+	if err != nil {
+		return notifications, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			n    = value.Notification{}
+			data []byte
+		)
+
+		err := rows.Scan(
+			&n.Id,
+			&n.RecipientId,
+			&n.Type,
+			&n.ActorId,
+			&n.QuestionId,
+			&n.AnswerId,
+			&data,
+			&n.ReadAt,
+			&n.CreatedAt,
+		)
+		if err != nil {
+			return []value.Notification{}, err
+		}
+
+		n.Data = data
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+func (r *Repository) CountUnread(ctx context.Context, recipientId string) (int, error) {
+	ctx, span := r.tracer.Start(ctx, "notification.Repository.CountUnread")
+	defer span.End()
+
+	var (
+		unread int
+		query  = `
+			SELECT COUNT(id) FROM notifications WHERE recipient_id = $1 AND read_at IS NULL
+		`
+	)
+
+	err := r.db.QueryRowContext(ctx, query, recipientId).Scan(&unread)
+
+	return unread, err
+}
+
+func (r *Repository) MarkRead(ctx context.Context, recipientId, id string) error {
+	ctx, span := r.tracer.Start(ctx, "notification.Repository.MarkRead")
+	defer span.End()
+
+	command := `
+		UPDATE notifications SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE id = $1 AND recipient_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, command, id, recipientId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) MarkAllRead(ctx context.Context, recipientId string) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "notification.Repository.MarkAllRead")
+	defer span.End()
+
+	command := `
+		UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE recipient_id = $1 AND read_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, command, recipientId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// GetPreferences returns the preference of every notification type, types
+// without a stored preference are enabled.
+func (r *Repository) GetPreferences(ctx context.Context, accountId string) ([]value.Preference, error) {
+	ctx, span := r.tracer.Start(ctx, "notification.Repository.GetPreferences")
+	defer span.End()
+
+	var (
+		preferences = []value.Preference{}
+		query       = `
+			SELECT t."type", COALESCE(p.enabled, TRUE)
+			FROM unnest($2::text[]) WITH ORDINALITY AS t("type", ordinality)
+			LEFT JOIN notification_preferences p ON p.account_id = $1 AND p."type" = t."type"
+			ORDER BY t.ordinality
+		`
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, accountId, pq.Array(value.Types))
+	if err != nil {
+		return preferences, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		preference := value.Preference{}
+
+		if err := rows.Scan(&preference.Type, &preference.Enabled); err != nil {
+			return []value.Preference{}, err
+		}
+
+		preferences = append(preferences, preference)
+	}
+
+	return preferences, rows.Err()
+}
+
+func (r *Repository) SetPreference(ctx context.Context, accountId string, p value.Preference) error {
+	ctx, span := r.tracer.Start(ctx, "notification.Repository.SetPreference")
+	defer span.End()
+
+	command := `
+		INSERT INTO notification_preferences (account_id, "type", enabled) VALUES ($1, $2, $3)
+		ON CONFLICT (account_id, "type") DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := r.db.ExecContext(ctx, command, accountId, p.Type, p.Enabled)
+
+	return err
+}
@@ -0,0 +1,94 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/notification/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	Service struct {
+		tracer trace.Tracer
+		repo   *Repository
+	}
+
+	Input struct {
+		Identity          identifier.Claim
+		NotificationId    string
+		NotificationQuery value.NotificationQuery
+		Preference        value.Preference
+	}
+)
+
+func NewService(repo *Repository, tracer trace.Tracer) *Service {
+	return &Service{
+		repo:   repo,
+		tracer: tracer,
+	}
+}
+
+func (s *Service) GetNotifications(ctx context.Context, input Input) (value.Inbox, error) {
+	ctx, span := s.tracer.Start(ctx, "notification.Service.GetNotifications")
+	defer span.End()
+
+	if err := value.ValidateNotificationQuery(input.NotificationQuery); err != nil {
+		return value.Inbox{}, err
+	}
+
+	notifications, err := s.repo.GetList(ctx, input.Identity.AccountId, input.NotificationQuery)
+	if err != nil {
+		return value.Inbox{}, err
+	}
+
+	unread, err := s.repo.CountUnread(ctx, input.Identity.AccountId)
+	if err != nil {
+		return value.Inbox{}, err
+	}
+
+	return value.Inbox{
+		Notifications: notifications,
+		Unread:        unread,
+	}, nil
+}
+
+func (s *Service) MarkRead(ctx context.Context, input Input) error {
+	ctx, span := s.tracer.Start(ctx, "notification.Service.MarkRead")
+	defer span.End()
+
+	if err := value.ValidateNotificationId(input.NotificationId); err != nil {
+		return err
+	}
+
+	return s.repo.MarkRead(ctx, input.Identity.AccountId, input.NotificationId)
+}
+
+func (s *Service) MarkAllRead(ctx context.Context, input Input) (int64, error) {
+	ctx, span := s.tracer.Start(ctx, "notification.Service.MarkAllRead")
+	defer span.End()
+
+	return s.repo.MarkAllRead(ctx, input.Identity.AccountId)
+}
+
+func (s *Service) GetPreferences(ctx context.Context, input Input) ([]value.Preference, error) {
+	ctx, span := s.tracer.Start(ctx, "notification.Service.GetPreferences")
+	defer span.End()
+
+	return s.repo.GetPreferences(ctx, input.Identity.AccountId)
+}
+
+func (s *Service) UpdatePreference(ctx context.Context, input Input) (value.Preference, error) {
+	ctx, span := s.tracer.Start(ctx, "notification.Service.UpdatePreference")
+	defer span.End()
+
+	if err := value.ValidatePreference(input.Preference); err != nil {
+		return value.Preference{}, err
+	}
+
+	if err := s.repo.SetPreference(ctx, input.Identity.AccountId, input.Preference); err != nil {
+		return value.Preference{}, err
+	}
+
+	return input.Preference, nil
+}
@@ -0,0 +1,125 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/rizface/quora/events"
+	"github.com/rizface/quora/notification/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Subscriber turns domain events into notifications. Comments and accepted
+// answers have notification types but no events produce them yet.
+type Subscriber struct {
+	tracer trace.Tracer
+	repo   *Repository
+}
+
+func NewSubscriber(repo *Repository, tracer trace.Tracer) *Subscriber {
+	return &Subscriber{
+		repo:   repo,
+		tracer: tracer,
+	}
+}
+
+func (s *Subscriber) Register(bus *events.Bus) {
+	events.Subscribe(bus, "notification.question_mentions", s.OnQuestionCreated)
+	events.Subscribe(bus, "notification.question_state", s.OnQuestionStateChanged)
+	events.Subscribe(bus, "notification.answer_followers", s.OnAnswerCreated)
+	events.Subscribe(bus, "notification.answer_mentions", s.OnAnswerMentions)
+	events.Subscribe(bus, "notification.answer_vote", s.OnAnswerVoted)
+}
+
+func (s *Subscriber) OnQuestionCreated(ctx context.Context, tx *sql.Tx, question events.QuestionCreated) error {
+	ctx, span := s.tracer.Start(ctx, "notification.Subscriber.OnQuestionCreated")
+	defer span.End()
+
+	return s.notifyMentioned(ctx, tx, question.Question, value.NewNotificationParam{
+		Type:       value.TypeMention,
+		ActorId:    question.AuthorId,
+		QuestionId: question.QuestionId,
+	})
+}
+
+func (s *Subscriber) OnQuestionStateChanged(ctx context.Context, tx *sql.Tx, changed events.QuestionStateChanged) error {
+	ctx, span := s.tracer.Start(ctx, "notification.Subscriber.OnQuestionStateChanged")
+	defer span.End()
+
+	n := value.NewNotification(value.NewNotificationParam{
+		Type:       value.TypeModeration,
+		ActorId:    changed.ActorId,
+		QuestionId: changed.QuestionId,
+		Data: map[string]string{
+			"state":       changed.State,
+			"reason":      changed.Reason,
+			"duplicateOf": changed.DuplicateOf,
+		},
+	})
+
+	return s.repo.Create(ctx, tx, n, []string{changed.AuthorId})
+}
+
+func (s *Subscriber) OnAnswerCreated(ctx context.Context, tx *sql.Tx, answer events.AnswerCreated) error {
+	ctx, span := s.tracer.Start(ctx, "notification.Subscriber.OnAnswerCreated")
+	defer span.End()
+
+	param := value.NewNotificationParam{
+		Type:       value.TypeAnswer,
+		ActorId:    answer.AnswererId,
+		QuestionId: answer.QuestionId,
+		AnswerId:   answer.AnswerId,
+	}
+
+	followers, err := s.repo.GetQuestionFollowers(ctx, answer.QuestionId)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Create(ctx, tx, value.NewNotification(param), followers)
+}
+
+// OnAnswerMentions is a subscriber of its own so a failure to notify the
+// mentioned accounts does not notify the followers twice.
+func (s *Subscriber) OnAnswerMentions(ctx context.Context, tx *sql.Tx, answer events.AnswerCreated) error {
+	ctx, span := s.tracer.Start(ctx, "notification.Subscriber.OnAnswerMentions")
+	defer span.End()
+
+	return s.notifyMentioned(ctx, tx, answer.Answer, value.NewNotificationParam{
+		Type:       value.TypeMention,
+		ActorId:    answer.AnswererId,
+		QuestionId: answer.QuestionId,
+		AnswerId:   answer.AnswerId,
+	})
+}
+
+func (s *Subscriber) OnAnswerVoted(ctx context.Context, tx *sql.Tx, vote events.AnswerVoted) error {
+	ctx, span := s.tracer.Start(ctx, "notification.Subscriber.OnAnswerVoted")
+	defer span.End()
+
+	n := value.NewNotification(value.NewNotificationParam{
+		Type:       value.TypeVote,
+		ActorId:    vote.VoterId,
+		QuestionId: vote.QuestionId,
+		AnswerId:   vote.AnswerId,
+		Data: map[string]string{
+			"vote": vote.Type,
+		},
+	})
+
+	return s.repo.Create(ctx, tx, n, []string{vote.AnswererId})
+}
+
+func (s *Subscriber) notifyMentioned(ctx context.Context, tx *sql.Tx, text string, param value.NewNotificationParam) error {
+	usernames := value.ParseMentions(text)
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	mentioned, err := s.repo.GetAccountsByUsernames(ctx, usernames)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Create(ctx, tx, value.NewNotification(param), mentioned)
+}
@@ -0,0 +1,166 @@
+package value
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/rizface/quora/nuller"
+)
+
+const (
+	TypeAnswer         = "answer.created"
+	TypeVote           = "answer.voted"
+	TypeComment        = "comment.created"
+	TypeMention        = "mention"
+	TypeAcceptedAnswer = "answer.accepted"
+	TypeModeration     = "moderation"
+)
+
+// Types lists every notification type an account can opt out of
+var Types = []string{TypeAnswer, TypeVote, TypeComment, TypeMention, TypeAcceptedAnswer, TypeModeration}
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@(\w{3,30})`)
+
+type (
+	Notification struct {
+		Id          string            `json:"id"`
+		RecipientId string            `json:"recipientId"`
+		Type        string            `json:"type"`
+		ActorId     nuller.NullString `json:"actorId"`
+		QuestionId  nuller.NullString `json:"questionId"`
+		AnswerId    nuller.NullString `json:"answerId"`
+		Data        json.RawMessage   `json:"data"`
+		ReadAt      *time.Time        `json:"readAt"`
+		CreatedAt   time.Time         `json:"createdAt"`
+	}
+
+	NewNotificationParam struct {
+		Type       string
+		ActorId    string
+		QuestionId string
+		AnswerId   string
+		Data       map[string]string
+	}
+
+	Inbox struct {
+		Notifications []Notification
+		Unread        int
+	}
+
+	NotificationQuery struct {
+		Limit      int
+		Skip       int
+		UnreadOnly bool
+	}
+
+	Preference struct {
+		Type    string `json:"type"`
+		Enabled bool   `json:"enabled"`
+	}
+)
+
+func NewNotification(p NewNotificationParam) Notification {
+	n := Notification{
+		Type:      p.Type,
+		Data:      json.RawMessage("{}"),
+		CreatedAt: time.Now(),
+	}
+
+	n.ActorId.String, n.ActorId.Valid = p.ActorId, p.ActorId != ""
+	n.QuestionId.String, n.QuestionId.Valid = p.QuestionId, p.QuestionId != ""
+	n.AnswerId.String, n.AnswerId.Valid = p.AnswerId, p.AnswerId != ""
+
+	if len(p.Data) > 0 {
+		// a map of strings can always be marshaled
+		n.Data, _ = json.Marshal(p.Data)
+	}
+
+	return n
+}
+
+// ParseMentions returns the lowercased usernames mentioned with @username,
+// every username is returned once.
+func ParseMentions(text string) []string {
+	var (
+		seen      = map[string]bool{}
+		usernames = []string{}
+	)
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		username := strings.ToLower(match[1])
+		if seen[username] {
+			continue
+		}
+
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+
+	return usernames
+}
+
+func NewNotificationQuery(url url.Values) (NotificationQuery, error) {
+	q := NotificationQuery{
+		Limit: 20,
+		Skip:  0,
+	}
+
+	if url.Has("limit") && url.Get("limit") != "" {
+		limit, err := strconv.Atoi(url.Get("limit"))
+		if err != nil {
+			return NotificationQuery{}, err
+		}
+
+		q.Limit = limit
+	}
+
+	if url.Has("skip") && url.Get("skip") != "" {
+		skip, err := strconv.Atoi(url.Get("skip"))
+		if err != nil {
+			return NotificationQuery{}, err
+		}
+
+		q.Skip = skip
+	}
+
+	if url.Has("unread") && url.Get("unread") != "" {
+		unread, err := strconv.ParseBool(url.Get("unread"))
+		if err != nil {
+			return NotificationQuery{}, err
+		}
+
+		q.UnreadOnly = unread
+	}
+
+	return q, nil
+}
+
+func ValidateNotificationQuery(q NotificationQuery) error {
+	return validation.Errors{
+		"limit": validation.Validate(q.Limit, validation.Min(1), validation.Max(100)),
+		"skip":  validation.Validate(q.Skip, validation.Min(0)),
+	}.Filter()
+}
+
+func ValidateNotificationId(id string) error {
+	return validation.Errors{
+		"id": validation.Validate(id, validation.Required, is.UUID),
+	}.Filter()
+}
+
+func ValidatePreference(p Preference) error {
+	types := make([]interface{}, 0, len(Types))
+	for _, t := range Types {
+		types = append(types, t)
+	}
+
+	return validation.Errors{
+		"type": validation.Validate(p.Type, validation.Required, validation.In(types...)),
+	}.Filter()
+}
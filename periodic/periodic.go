@@ -0,0 +1,82 @@
+package periodic
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Worker runs a function in the background, once when it starts and then
+// every interval until it is stopped. The context of the function is
+// cancelled by Stop, so a run in progress returns early.
+type Worker struct {
+	interval time.Duration
+	wake     chan struct{}
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewWorker(interval time.Duration) *Worker {
+	return &Worker{
+		interval: interval,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+func (w *Worker) Start(run func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	w.wg.Add(1)
+
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.wake:
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Wake runs the function without waiting for the interval. A wake while the
+// function is running runs it once more right after.
+func (w *Worker) Wake() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Stop waits for the run in progress to return, or for ctx to be done.
+func (w *Worker) Stop(ctx context.Context) error {
+	if w.cancel == nil {
+		return nil
+	}
+
+	w.cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"os"
+
+	"github.com/rizface/quora/mailer"
+)
+
+// ProvideMailer sends through SMTP when SMTP_HOST is set, otherwise the
+// messages are only logged.
+func ProvideMailer() mailer.Sender {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return mailer.LogSender{}
+	}
+
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "no-reply@quora.local"
+	}
+
+	return mailer.NewSMTPSender(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from)
+}
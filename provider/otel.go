@@ -1,47 +1,162 @@
 package provider
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"strconv"
+	"sync"
 
+	"github.com/rizface/quora/health"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
-// collector url
-func newTraceProvider(url string) (*sdktrace.TracerProvider, error) {
-	expr, err := jaeger.New(
-		jaeger.WithCollectorEndpoint(
-			jaeger.WithEndpoint(url),
-		),
-	)
+// newExporter picks the exporter of OTEL_TRACES_EXPORTER:
+//   - otlp sends to OTEL_EXPORTER_OTLP_ENDPOINT over the protocol of
+//     OTEL_EXPORTER_OTLP_PROTOCOL, grpc or http/protobuf
+//   - jaeger sends to JAEGER_EXPORTER_URL, it is deprecated in favor of otlp
+//   - stdout prints the spans, for local debugging
+//   - none drops the spans
+//
+// Without OTEL_TRACES_EXPORTER it is jaeger when JAEGER_EXPORTER_URL is set,
+// none otherwise. The OTLP exporters read the rest of the OTEL_EXPORTER_OTLP_*
+// variables themselves.
+func newExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	exporter := os.Getenv("OTEL_TRACES_EXPORTER")
+	if exporter == "" && os.Getenv("JAEGER_EXPORTER_URL") != "" {
+		exporter = "jaeger"
+	}
+
+	switch exporter {
+	case "otlp":
+		switch protocol := os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"); protocol {
+		case "", "grpc":
+			return otlptracegrpc.New(ctx)
+		case "http/protobuf":
+			return otlptracehttp.New(ctx)
+		default:
+			return nil, fmt.Errorf("unsupported otlp protocol %q", protocol)
+		}
+	case "jaeger":
+		return jaeger.New(
+			jaeger.WithCollectorEndpoint(
+				jaeger.WithEndpoint(os.Getenv("JAEGER_EXPORTER_URL")),
+			),
+		)
+	case "stdout":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "", "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported traces exporter %q", exporter)
+	}
+}
+
+// newSampler samples the ratio of OTEL_TRACES_SAMPLER_ARG of the new traces, 1
+// by default. A trace that comes with a parent keeps the decision of the
+// parent, so the services of a trace sample it together.
+func newSampler() (sdktrace.Sampler, error) {
+	ratio := 1.0
+
+	if arg := os.Getenv("OTEL_TRACES_SAMPLER_ARG"); arg != "" {
+		var err error
+
+		ratio, err = strconv.ParseFloat(arg, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return nil, fmt.Errorf("invalid sampling ratio %q", arg)
+		}
+	}
+
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio)), nil
+}
+
+// monitoredExporter keeps the error of the last export, so the readiness
+// tells when the spans are dropped.
+type monitoredExporter struct {
+	sdktrace.SpanExporter
+
+	mu  sync.Mutex
+	err error
+}
+
+func (e *monitoredExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+
+	e.mu.Lock()
+	e.err = err
+	e.mu.Unlock()
+
+	return err
+}
+
+func (e *monitoredExporter) Check(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.err != nil {
+		return fmt.Errorf("last export failed: %w", e.err)
+	}
+
+	return nil
+}
+
+func newTraceProvider(ctx context.Context) (*sdktrace.TracerProvider, health.Check, error) {
+	exporter, err := newExporter(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(expr),
+	sampler, err := newSampler()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sampler),
 		sdktrace.WithResource(resource.NewWithAttributes(
 			semconv.SchemaURL,
 			semconv.ServiceName(os.Getenv("APP_NAME")),
 			attribute.String("environment", os.Getenv("QUORA_ENV")),
 		)),
-	), nil
+	}
+
+	// the spans are still created without an exporter, so the trace ids are
+	// propagated and reported in the responses
+	if exporter == nil {
+		return sdktrace.NewTracerProvider(opts...), func(ctx context.Context) error { return nil }, nil
+	}
+
+	monitored := &monitoredExporter{SpanExporter: exporter}
+
+	opts = append(opts, sdktrace.WithBatcher(monitored))
+
+	return sdktrace.NewTracerProvider(opts...), monitored.Check, nil
 }
 
-func ProvideOtel() (*sdktrace.TracerProvider, trace.Tracer, error) {
-	traceProvider, err := newTraceProvider(os.Getenv("JAEGER_EXPORTER_URL"))
+// ProvideOtel also returns the readiness check of the exporter
+func ProvideOtel() (*sdktrace.TracerProvider, trace.Tracer, health.Check, error) {
+	traceProvider, check, err := newTraceProvider(context.Background())
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	otel.SetTracerProvider(traceProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
 
 	tp := traceProvider.Tracer("quora-clone")
 
-	return traceProvider, tp, nil
+	return traceProvider, tp, check, nil
 }
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rizface/quora/cache"
+	"github.com/rizface/quora/idempotency"
+	"github.com/rizface/quora/ratelimit"
+)
+
+// ProvideRedis connects to REDIS_ADDR, it returns a nil client when Redis is
+// not configured and the stores are kept in the memory of the process.
+func ProvideRedis() (*redis.Client, error) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		return nil, nil
+	}
+
+	db, err := strconv.Atoi(os.Getenv("REDIS_DB"))
+	if err != nil {
+		db = 0
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: os.Getenv("REDIS_PASSWORD"),
+		DB:       db,
+	})
+
+	return client, client.Ping(context.Background()).Err()
+}
+
+func ProvideCacheStore(client *redis.Client) cache.Store {
+	if client == nil {
+		return cache.NewMemoryStore()
+	}
+
+	return cache.NewRedisStore(client)
+}
+
+func ProvideRateLimitStore(client *redis.Client) ratelimit.Store {
+	if client == nil {
+		return ratelimit.NewMemoryStore()
+	}
+
+	return ratelimit.NewRedisStore(client)
+}
+
+func ProvideIdempotencyStore(client *redis.Client) idempotency.Store {
+	if client == nil {
+		return idempotency.NewMemoryStore()
+	}
+
+	return idempotency.NewRedisStore(client)
+}
@@ -3,11 +3,18 @@ package provider
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rizface/quora/metrics"
+	"github.com/rizface/quora/requestlog"
+	"github.com/rizface/quora/tracing"
 )
 
 func ProvideRouter() *chi.Mux {
 	r := chi.NewRouter()
 
+	r.Use(tracing.Middleware)
+	// the access log sees the 500 of a recovered panic
+	r.Use(requestlog.Middleware)
+	r.Use(metrics.Middleware)
 	r.Use(middleware.Recoverer)
 
 	return r
@@ -11,3 +11,18 @@ func ProviderServer(h http.Handler) *http.Server {
 		Handler: h,
 	}
 }
+
+// ProvideAdminServer serves the admin endpoints on ADMIN_PORT, away from the
+// public port. It returns nil when ADMIN_PORT is not set and the endpoints are
+// served by the app.
+func ProvideAdminServer(h http.Handler) *http.Server {
+	addr := os.Getenv("ADMIN_PORT")
+	if addr == "" {
+		return nil
+	}
+
+	return &http.Server{
+		Addr:    addr,
+		Handler: h,
+	}
+}
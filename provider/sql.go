@@ -4,11 +4,16 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/rizface/quora/sqltrace"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func ProvideSQL() (*sql.DB, error) {
+// PostgresDSN is the connection string of the database, it is also used by
+// connections opened outside of the pool such as LISTEN/NOTIFY listeners.
+func PostgresDSN() string {
 	var (
 		host     = os.Getenv("PG_HOST")
 		port     = os.Getenv("PG_PORT")
@@ -17,12 +22,29 @@ func ProvideSQL() (*sql.DB, error) {
 		dbname   = os.Getenv("PG_DBNAME")
 	)
 
-	sql, err := sql.Open("postgres", fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
 		user, password, host, port, dbname,
-	))
+	)
+}
+
+// ProvideSQL traces every statement as a child of the span of its context and
+// logs the statements slower than SQL_SLOW_QUERY_THRESHOLD, 200ms by default.
+func ProvideSQL(tracer trace.Tracer) (*sql.DB, error) {
+	connector, err := pq.NewConnector(PostgresDSN())
 	if err != nil {
 		return nil, err
 	}
 
+	threshold, err := time.ParseDuration(os.Getenv("SQL_SLOW_QUERY_THRESHOLD"))
+	if err != nil || threshold < 0 {
+		threshold = 200 * time.Millisecond
+	}
+
+	sql := sql.OpenDB(sqltrace.NewConnector(connector, sqltrace.Options{
+		Tracer:        tracer,
+		System:        "postgresql",
+		SlowThreshold: threshold,
+	}))
+
 	return sql, sql.Ping()
 }
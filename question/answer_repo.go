@@ -4,12 +4,20 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"log"
+	"fmt"
 
+	"github.com/rizface/quora/events"
 	"github.com/rizface/quora/question/value"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// answerOrders maps every supported answer sort to its ORDER BY expression.
+var answerOrders = map[string]string{
+	value.AnswerSortScore:  "a.score DESC, a.created_at ASC",
+	value.AnswerSortUpvote: "a.upvote DESC, a.created_at ASC",
+	value.AnswerSortNew:    "a.created_at DESC",
+}
+
 type (
 	AnswerRepo struct {
 		db     *sql.DB
@@ -17,8 +25,10 @@ type (
 	}
 
 	CreateAnswerReq struct {
-		answer   value.Answer
-		question value.QuestionEntity
+		answer     value.Answer
+		question   value.QuestionEntity
+		event      events.Event
+		moderation *value.ModerationEntry
 	}
 )
 
@@ -43,11 +53,33 @@ func (a *AnswerRepo) Create(ctx context.Context, req CreateAnswerReq) (value.Ans
 	`
 	)
 
-	_, err := a.db.ExecContext(ctx, command, answer.Id, question.Id, answer.AnswererId, answer.Answer, answer.CreatedAt, answer.UpdatedAt)
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return value.Answer{}, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	_, err = tx.ExecContext(ctx, command, answer.Id, question.Id, answer.AnswererId, answer.Answer, answer.CreatedAt, answer.UpdatedAt)
 	if err != nil {
 		return value.Answer{}, err
 	}
 
+	if err := markHotScoreStale(ctx, tx, question.Id); err != nil {
+		return value.Answer{}, err
+	}
+
+	if err := queueModeration(ctx, tx, req.moderation); err != nil {
+		return value.Answer{}, err
+	}
+
+	if err := events.Record(ctx, tx, req.event); err != nil {
+		return value.Answer{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return value.Answer{}, err
+	}
+
 	return answer, nil
 }
 
@@ -58,7 +90,7 @@ func (a *AnswerRepo) GetOne(ctx context.Context, answerId string) (value.Answer,
 	var (
 		answer = value.Answer{}
 		query  = `
-			SELECT id, question_id, answerer_id, answer, upvote, downvote, created_at, updated_at FROM answers WHERE id = $1
+			SELECT id, question_id, answerer_id, answer, upvote, downvote, score, created_at, updated_at FROM answers WHERE id = $1
 		`
 	)
 
@@ -70,6 +102,7 @@ func (a *AnswerRepo) GetOne(ctx context.Context, answerId string) (value.Answer,
 			&answer.Answer,
 			&answer.Upvote,
 			&answer.Downvote,
+			&answer.Score,
 			&answer.CreatedAt,
 			&answer.UpdatedAt,
 		)
@@ -84,37 +117,21 @@ func (a *AnswerRepo) GetOne(ctx context.Context, answerId string) (value.Answer,
 	return answer, nil
 }
 
-func (a *AnswerRepo) Vote(ctx context.Context, q value.Answer, v value.Vote) error {
+func (a *AnswerRepo) Vote(ctx context.Context, q value.Answer, v value.Vote, e events.Event) error {
 	ctx, span := a.tracer.Start(ctx, "question.AnswerRepo.Vote")
 	defer span.End()
 
-	tx, err := a.db.Begin()
-
-	defer func(err error) {
-		if err != nil {
-			if err := tx.Rollback(); err != nil {
-				log.Println(err)
-			}
-
-			return
-		}
-
-		if err := tx.Commit(); err != nil {
-			log.Println(err)
-
-			return
-		}
-	}(err)
-
+	tx, err := a.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback() //nolint:errcheck
 
 	command := `
-		UPDATE answers SET upvote = $1, downvote = $2, updated_at = $3 WHERE id = $4
+		UPDATE answers SET upvote = $1, downvote = $2, score = $3, updated_at = $4 WHERE id = $5
 	`
 
-	if _, err := tx.ExecContext(ctx, command, q.Upvote, q.Downvote, q.UpdatedAt, q.Id); err != nil {
+	if _, err := tx.ExecContext(ctx, command, q.Upvote, q.Downvote, q.Score, q.UpdatedAt, q.Id); err != nil {
 		return err
 	}
 
@@ -123,9 +140,65 @@ func (a *AnswerRepo) Vote(ctx context.Context, q value.Answer, v value.Vote) err
 	`
 
 	if _, err := tx.ExecContext(ctx, command, v.VoterId, v.AnswerId, v.Type); err != nil {
-		log.Println(err)
 		return err
 	}
 
-	return nil
+	if err := markHotScoreStale(ctx, tx, q.QuestionId); err != nil {
+		return err
+	}
+
+	if err := events.Record(ctx, tx, e); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (a *AnswerRepo) GetList(ctx context.Context, q value.AnswerQuery) ([]value.Answer, error) {
+	ctx, span := a.tracer.Start(ctx, "question.AnswerRepo.GetList")
+	defer span.End()
+
+	var (
+		answers = []value.Answer{}
+		query   = fmt.Sprintf(`
+			SELECT a.id, a.question_id, a.answerer_id, a.answer, a.upvote, a.downvote, a.score, a.created_at, a.updated_at,
+			ac.id, ac.username
+			FROM answers a
+			INNER JOIN accounts ac ON ac.id = a.answerer_id
+			WHERE a.question_id = $1
+			ORDER BY %s
+			LIMIT $2 OFFSET $3
+		`, answerOrders[q.Sort])
+	)
+
+	rows, err := a.db.QueryContext(ctx, query, q.QuestionId, q.Limit, q.Skip)
+	if err != nil {
+		return answers, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		answer := value.Answer{}
+
+		err := rows.Scan(
+			&answer.Id,
+			&answer.QuestionId,
+			&answer.AnswererId,
+			&answer.Answer,
+			&answer.Upvote,
+			&answer.Downvote,
+			&answer.Score,
+			&answer.CreatedAt,
+			&answer.UpdatedAt,
+			&answer.Answerer.Id,
+			&answer.Answerer.Username,
+		)
+		if err != nil {
+			return []value.Answer{}, err
+		}
+
+		answers = append(answers, answer)
+	}
+
+	return answers, rows.Err()
 }
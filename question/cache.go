@@ -0,0 +1,26 @@
+package question
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rizface/quora/question/value"
+)
+
+// every page of every sort is cached under the version of the namespace, a
+// write bumps the version instead of looking the pages up
+const listNamespace = "questions:list"
+
+func listKey(version string, q value.QuestionQuery) string {
+	return fmt.Sprintf("%s:%s:%s:%s:%d:%d:%s",
+		listNamespace, version, q.Sort, q.Window, q.Limit, q.Skip, strings.Join(q.SpaceIds, ","),
+	)
+}
+
+func questionKey(questionId string) string {
+	return fmt.Sprintf("questions:%s", questionId)
+}
+
+func authorKey(authorId string) string {
+	return fmt.Sprintf("authors:%s", authorId)
+}
@@ -1,6 +1,11 @@
 package question
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rizface/quora/question/value"
+)
 
 var (
 	ErrAuthorNotFound   = errors.New("author not found")
@@ -8,4 +13,45 @@ var (
 	ErrVoteNotFound     = errors.New("question not found")
 	ErrAnswerNotFound   = errors.New("answer not found")
 	ErrNotTheAuthor     = errors.New("not the author")
+	ErrNotModerator     = errors.New("not a moderator")
+	ErrInvalidDuplicate = errors.New("question can not be a duplicate of itself")
+	ErrQuestionClosed   = errors.New("question is closed")
+	ErrQuestionLocked   = errors.New("question is locked")
+	ErrQuestionIsOpen   = errors.New("question is already open")
+	ErrNotFollowing     = errors.New("question is not followed")
+
+	ErrFilterConfigNotFound = errors.New("filter config not found")
 )
+
+// StateError is returned when an action is not allowed by the current state of
+// the question. It matches ErrQuestionClosed or ErrQuestionLocked with errors.Is.
+type StateError struct {
+	State  string
+	Reason string
+}
+
+func newStateError(q value.QuestionEntity) StateError {
+	return StateError{
+		State:  q.State,
+		Reason: q.StateReason.String,
+	}
+}
+
+func (e StateError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("question is %s", e.State)
+	}
+
+	return fmt.Sprintf("question is %s: %s", e.State, e.Reason)
+}
+
+func (e StateError) Is(target error) bool {
+	switch e.State {
+	case value.QuestionStateClosed:
+		return target == ErrQuestionClosed
+	case value.QuestionStateLocked:
+		return target == ErrQuestionLocked
+	}
+
+	return false
+}
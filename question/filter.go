@@ -0,0 +1,256 @@
+package question
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/rizface/quora/question/value"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+`)
+
+type (
+	// Filter inspects submitted content and decides whether it can be published,
+	// must be rejected, or has to be reviewed by a moderator.
+	Filter interface {
+		Check(ctx context.Context, c value.Content) (value.FilterResult, error)
+	}
+
+	FilterChain []Filter
+
+	BannedWordFilter struct {
+		words []string
+	}
+
+	LinkLimitFilter struct {
+		max int
+	}
+
+	ShoutingFilter struct {
+		maxUppercaseRatio float64
+		maxRepeatedChars  int
+	}
+
+	DuplicateFilter struct {
+		repo   *FilterRepo
+		window int
+	}
+
+	ContentFilter struct {
+		tracer   trace.Tracer
+		repo     *FilterRepo
+		defaults value.FilterConfig
+	}
+)
+
+// Run executes every filter in order. A rejection stops the chain immediately,
+// while a moderation verdict is kept until the end so that a later filter can
+// still reject the content.
+func (c FilterChain) Run(ctx context.Context, content value.Content) (value.FilterResult, error) {
+	result := value.Allow()
+
+	for _, f := range c {
+		r, err := f.Check(ctx, content)
+		if err != nil {
+			return value.FilterResult{}, err
+		}
+
+		if r.IsRejected() {
+			return r, nil
+		}
+
+		if r.NeedsModeration() && !result.NeedsModeration() {
+			result = r
+		}
+	}
+
+	return result, nil
+}
+
+func NewBannedWordFilter(words []string) BannedWordFilter {
+	lowered := make([]string, 0, len(words))
+	for _, w := range words {
+		lowered = append(lowered, strings.ToLower(w))
+	}
+
+	return BannedWordFilter{words: lowered}
+}
+
+func (f BannedWordFilter) Check(ctx context.Context, c value.Content) (value.FilterResult, error) {
+	words := strings.FieldsFunc(strings.ToLower(c.Text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	for _, word := range words {
+		for _, banned := range f.words {
+			if word == banned {
+				return value.Reject(fmt.Sprintf("contains banned word %q", banned)), nil
+			}
+		}
+	}
+
+	return value.Allow(), nil
+}
+
+func NewLinkLimitFilter(max int) LinkLimitFilter {
+	return LinkLimitFilter{max: max}
+}
+
+func (f LinkLimitFilter) Check(ctx context.Context, c value.Content) (value.FilterResult, error) {
+	if links := len(linkPattern.FindAllString(c.Text, -1)); links > f.max {
+		return value.Reject(fmt.Sprintf("contains %d links, the maximum is %d", links, f.max)), nil
+	}
+
+	return value.Allow(), nil
+}
+
+func NewShoutingFilter(maxUppercaseRatio float64, maxRepeatedChars int) ShoutingFilter {
+	return ShoutingFilter{
+		maxUppercaseRatio: maxUppercaseRatio,
+		maxRepeatedChars:  maxRepeatedChars,
+	}
+}
+
+func (f ShoutingFilter) Check(ctx context.Context, c value.Content) (value.FilterResult, error) {
+	var (
+		letters, uppercase, repeated int
+		previous                     rune
+	)
+
+	for _, r := range c.Text {
+		if unicode.IsLetter(r) {
+			letters++
+
+			if unicode.IsUpper(r) {
+				uppercase++
+			}
+		}
+
+		if r == previous && !unicode.IsSpace(r) {
+			repeated++
+		} else {
+			repeated = 1
+		}
+
+		if f.maxRepeatedChars > 0 && repeated > f.maxRepeatedChars {
+			return value.Moderate("contains too many repeated characters"), nil
+		}
+
+		previous = r
+	}
+
+	// short texts like "FAQ" or "SQL?" are not shouting
+	if letters >= 10 && float64(uppercase)/float64(letters) > f.maxUppercaseRatio {
+		return value.Moderate("contains too many uppercase letters"), nil
+	}
+
+	return value.Allow(), nil
+}
+
+func NewDuplicateFilter(repo *FilterRepo, window int) DuplicateFilter {
+	return DuplicateFilter{
+		repo:   repo,
+		window: window,
+	}
+}
+
+func (f DuplicateFilter) Check(ctx context.Context, c value.Content) (value.FilterResult, error) {
+	if f.window < 1 {
+		return value.Allow(), nil
+	}
+
+	posts, err := f.repo.GetRecentPosts(ctx, c, f.window)
+	if err != nil {
+		return value.FilterResult{}, err
+	}
+
+	text := normalizeText(c.Text)
+
+	for _, post := range posts {
+		if normalizeText(post) == text {
+			return value.Moderate(fmt.Sprintf("duplicate of one of the author's last %d posts", f.window)), nil
+		}
+	}
+
+	return value.Allow(), nil
+}
+
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+func NewContentFilter(repo *FilterRepo, tracer trace.Tracer) *ContentFilter {
+	return &ContentFilter{
+		tracer:   tracer,
+		repo:     repo,
+		defaults: value.DefaultFilterConfig(),
+	}
+}
+
+func (c *ContentFilter) chain(config value.FilterConfig) FilterChain {
+	return FilterChain{
+		NewBannedWordFilter(config.BannedWords),
+		NewLinkLimitFilter(config.MaxLinks),
+		NewShoutingFilter(config.MaxUppercaseRatio, config.MaxRepeatedChars),
+		NewDuplicateFilter(c.repo, config.DuplicateWindow),
+	}
+}
+
+// Check runs the filter chain configured for the space of the content. Rejected
+// content is reported as validation.Errors keyed by the content type, so it
+// reaches clients the same way as any other validation error.
+func (c *ContentFilter) Check(ctx context.Context, content value.Content) (value.FilterResult, error) {
+	ctx, span := c.tracer.Start(ctx, "question.ContentFilter.Check")
+	defer span.End()
+
+	config := c.defaults
+
+	if content.SpaceId.Valid {
+		spaceConfig, err := c.repo.GetSpaceConfig(ctx, content.SpaceId.String)
+		if err != nil && !errors.Is(err, ErrFilterConfigNotFound) {
+			return value.FilterResult{}, err
+		}
+
+		if err == nil {
+			config = spaceConfig
+		}
+	}
+
+	result, err := c.chain(config).Run(ctx, content)
+	if err != nil {
+		return value.FilterResult{}, err
+	}
+
+	span.SetAttributes(
+		attribute.String("verdict", result.Verdict),
+		attribute.String("reason", result.Reason),
+	)
+
+	if result.IsRejected() {
+		return result, validation.Errors{
+			content.Type: errors.New(result.Reason),
+		}
+	}
+
+	return result, nil
+}
+
+// Moderation returns the entry of the moderation queue of content that passed
+// the chain with a moderation verdict, nil otherwise. The entry is written in
+// the transaction of the content, which stays published.
+func (c *ContentFilter) Moderation(content value.Content, result value.FilterResult) *value.ModerationEntry {
+	if !result.NeedsModeration() {
+		return nil
+	}
+
+	entry := value.NewModerationEntry(content, result)
+
+	return &entry
+}
@@ -0,0 +1,111 @@
+package question
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/rizface/quora/question/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type FilterRepo struct {
+	db     *sql.DB
+	tracer trace.Tracer
+}
+
+func NewFilterRepo(db *sql.DB, tracer trace.Tracer) *FilterRepo {
+	return &FilterRepo{
+		db:     db,
+		tracer: tracer,
+	}
+}
+
+func (f *FilterRepo) GetSpaceConfig(ctx context.Context, spaceId string) (value.FilterConfig, error) {
+	ctx, span := f.tracer.Start(ctx, "question.FilterRepo.GetSpaceConfig")
+	defer span.End()
+
+	var (
+		config = value.FilterConfig{}
+		query  = `
+			SELECT banned_words, max_links, max_uppercase_ratio, max_repeated_chars, duplicate_window
+			FROM space_content_filters WHERE space_id = $1
+		`
+	)
+
+	err := f.db.
+		QueryRowContext(ctx, query, spaceId).
+		Scan(
+			pq.Array(&config.BannedWords),
+			&config.MaxLinks,
+			&config.MaxUppercaseRatio,
+			&config.MaxRepeatedChars,
+			&config.DuplicateWindow,
+		)
+	if errors.Is(err, sql.ErrNoRows) {
+		return value.FilterConfig{}, ErrFilterConfigNotFound
+	}
+
+	if err != nil {
+		return value.FilterConfig{}, err
+	}
+
+	return config, nil
+}
+
+func (f *FilterRepo) GetRecentPosts(ctx context.Context, c value.Content, limit int) ([]string, error) {
+	ctx, span := f.tracer.Start(ctx, "question.FilterRepo.GetRecentPosts")
+	defer span.End()
+
+	var (
+		posts = []string{}
+		query = `
+			SELECT question FROM questions WHERE author_id = $1 ORDER BY created_at DESC LIMIT $2
+		`
+	)
+
+	if c.Type == value.ContentTypeAnswer {
+		query = `
+			SELECT answer FROM answers WHERE answerer_id = $1 ORDER BY created_at DESC LIMIT $2
+		`
+	}
+
+	rows, err := f.db.QueryContext(ctx, query, c.AuthorId, limit)
+	if err != nil {
+		return posts, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var post sql.NullString
+
+		if err := rows.Scan(&post); err != nil {
+			return []string{}, err
+		}
+
+		posts = append(posts, post.String)
+	}
+
+	return posts, rows.Err()
+}
+
+// queueModeration writes the entry with the transaction of the content, a nil
+// entry is not queued.
+func queueModeration(ctx context.Context, tx *sql.Tx, entry *value.ModerationEntry) error {
+	if entry == nil {
+		return nil
+	}
+
+	command := `
+		INSERT INTO moderation_queue (id, content_type, content_id, author_id, space_id, reason) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)
+	`
+
+	_, err := tx.ExecContext(ctx, command,
+		entry.Id, entry.ContentType, entry.ContentId, entry.AuthorId, entry.SpaceId, entry.Reason,
+	)
+
+	return err
+}
@@ -0,0 +1,106 @@
+package question
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/rizface/quora/question/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type FollowRepo struct {
+	db     *sql.DB
+	tracer trace.Tracer
+}
+
+func NewFollowRepo(db *sql.DB, tracer trace.Tracer) *FollowRepo {
+	return &FollowRepo{
+		db:     db,
+		tracer: tracer,
+	}
+}
+
+func (f *FollowRepo) Follow(ctx context.Context, question value.QuestionEntity, accountId string) error {
+	ctx, span := f.tracer.Start(ctx, "question.FollowRepo.Follow")
+	defer span.End()
+
+	command := `
+		INSERT INTO question_followers (question_id, account_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+	`
+
+	_, err := f.db.ExecContext(ctx, command, question.Id, accountId)
+
+	return err
+}
+
+func (f *FollowRepo) Unfollow(ctx context.Context, question value.QuestionEntity, accountId string) error {
+	ctx, span := f.tracer.Start(ctx, "question.FollowRepo.Unfollow")
+	defer span.End()
+
+	command := `
+		DELETE FROM question_followers WHERE question_id = $1 AND account_id = $2
+	`
+
+	result, err := f.db.ExecContext(ctx, command, question.Id, accountId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotFollowing
+	}
+
+	return nil
+}
+
+func (f *FollowRepo) GetFollowed(ctx context.Context, accountId string, q value.QuestionQuery) ([]value.QuestionEntity, error) {
+	ctx, span := f.tracer.Start(ctx, "question.FollowRepo.GetFollowed")
+	defer span.End()
+
+	var (
+		questions = []value.QuestionEntity{}
+		query     = `
+			SELECT q.id, q.author_id, q.space_id, q.question, q.tags, q.state, q.state_reason, q.created_at, q.updated_at
+			FROM question_followers qf
+			INNER JOIN questions q ON q.id = qf.question_id
+			WHERE qf.account_id = $1
+			ORDER BY qf.created_at DESC
+			LIMIT $2 OFFSET $3
+		`
+	)
+
+	rows, err := f.db.QueryContext(ctx, query, accountId, q.Limit, q.Skip)
+	if err != nil {
+		return questions, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		question := value.QuestionEntity{}
+
+		err := rows.Scan(
+			&question.Id,
+			&question.AuthorId,
+			&question.SpaceId,
+			&question.Question,
+			pq.Array(&question.Tags),
+			&question.State,
+			&question.StateReason,
+			&question.CreatedAt,
+			&question.UpdatedAt,
+		)
+		if err != nil {
+			return []value.QuestionEntity{}, err
+		}
+
+		questions = append(questions, question)
+	}
+
+	return questions, rows.Err()
+}
@@ -1,6 +1,7 @@
 package question
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -33,7 +34,7 @@ func (h *Handler) CreateQuestion(w http.ResponseWriter, r *http.Request) {
 
 	identity, err := identifier.GetFromContext(r.Context())
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusUnauthorized,
 			Info: err.Error(),
 		})
@@ -45,7 +46,7 @@ func (h *Handler) CreateQuestion(w http.ResponseWriter, r *http.Request) {
 
 	err = json.NewDecoder(r.Body).Decode(&payload)
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusBadRequest,
 			Info: "failed decode payload",
 		})
@@ -60,7 +61,7 @@ func (h *Handler) CreateQuestion(w http.ResponseWriter, r *http.Request) {
 
 	vErr := validation.Errors{}
 	if errors.As(err, &vErr) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusBadRequest,
 			Data: map[string]interface{}{"doc": vErr},
 			Info: "validation error",
@@ -70,21 +71,30 @@ func (h *Handler) CreateQuestion(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusInternalServerError,
 			Info: err.Error(),
 		})
 
 		span.RecordError(err)
-		span.SetStatus(codes.Error, fmt.Sprintln("error while create new question: %v", err))
+		span.SetStatus(codes.Error, fmt.Sprintf("error while create new question: %v", err))
 
 		return
 	}
 
-	stdres.Writer(w, stdres.Response{
+	// suggestions are best effort, the question is already created at this point
+	similar, err := h.svc.GetSimilarQuestions(ctx, Input{
+		SimilarQuery: value.NewSimilarQueryFromQuestion(question),
+	})
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	stdres.Writer(w, r, stdres.Response{
 		Code: http.StatusOK,
 		Data: map[string]interface{}{
-			"doc": question,
+			"doc":     question,
+			"similar": similar,
 		},
 		Info: "success",
 	})
@@ -96,7 +106,7 @@ func (h *Handler) GetQuestion(w http.ResponseWriter, r *http.Request) {
 
 	identity, err := identifier.GetFromContext(r.Context())
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusUnauthorized,
 			Info: err.Error(),
 		})
@@ -106,7 +116,7 @@ func (h *Handler) GetQuestion(w http.ResponseWriter, r *http.Request) {
 
 	query, err := value.NewQuestionQuery(r.URL.Query())
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusBadRequest,
 			Info: "invalid query parameter",
 		})
@@ -121,7 +131,7 @@ func (h *Handler) GetQuestion(w http.ResponseWriter, r *http.Request) {
 
 	vErr := validation.Errors{}
 	if errors.As(err, &vErr) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusBadRequest,
 			Info: "validation error",
 			Data: map[string]interface{}{"doc": vErr},
@@ -131,7 +141,7 @@ func (h *Handler) GetQuestion(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusInternalServerError,
 			Info: err.Error(),
 		})
@@ -142,7 +152,7 @@ func (h *Handler) GetQuestion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stdres.Writer(w, stdres.Response{
+	stdres.Writer(w, r, stdres.Response{
 		Code: http.StatusOK,
 		Info: "success",
 		Data: map[string]interface{}{
@@ -158,7 +168,7 @@ func (h *Handler) Vote(w http.ResponseWriter, r *http.Request) {
 
 	identity, err := identifier.GetFromContext(r.Context())
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusUnauthorized,
 			Info: err.Error(),
 		})
@@ -171,7 +181,7 @@ func (h *Handler) Vote(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&vote); err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusBadRequest,
 			Info: "failed parse payload",
 		})
@@ -186,7 +196,7 @@ func (h *Handler) Vote(w http.ResponseWriter, r *http.Request) {
 
 	vErr := validation.Errors{}
 	if errors.As(err, &vErr) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusBadRequest,
 			Data: map[string]interface{}{
 				"doc": vErr,
@@ -198,7 +208,7 @@ func (h *Handler) Vote(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if errors.Is(err, ErrAnswerNotFound) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusNotFound,
 			Info: err.Error(),
 		})
@@ -206,8 +216,17 @@ func (h *Handler) Vote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if errors.Is(err, ErrQuestionLocked) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusLocked,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusInternalServerError,
 			Info: err.Error(),
 		})
@@ -218,7 +237,7 @@ func (h *Handler) Vote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stdres.Writer(w, stdres.Response{
+	stdres.Writer(w, r, stdres.Response{
 		Code: http.StatusOK,
 		Info: "success",
 		Data: map[string]interface{}{
@@ -233,7 +252,7 @@ func (h *Handler) AnswerQuestion(w http.ResponseWriter, r *http.Request) {
 
 	identity, err := identifier.GetFromContext(r.Context())
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusUnauthorized,
 			Info: err.Error(),
 		})
@@ -246,10 +265,12 @@ func (h *Handler) AnswerQuestion(w http.ResponseWriter, r *http.Request) {
 	)
 
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusBadRequest,
 			Info: "failed decode answer payload",
 		})
+
+		return
 	}
 
 	answer, err := h.svc.Answer(ctx, Input{
@@ -259,7 +280,7 @@ func (h *Handler) AnswerQuestion(w http.ResponseWriter, r *http.Request) {
 
 	vErr := validation.Errors{}
 	if errors.As(err, &vErr) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusBadRequest,
 			Data: map[string]interface{}{"doc": vErr},
 		})
@@ -268,7 +289,7 @@ func (h *Handler) AnswerQuestion(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if errors.Is(err, ErrQuestionNotFound) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusNotFound,
 			Info: err.Error(),
 		})
@@ -279,8 +300,26 @@ func (h *Handler) AnswerQuestion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if errors.Is(err, ErrQuestionClosed) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusConflict,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrQuestionLocked) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusLocked,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusInternalServerError,
 			Info: err.Error(),
 		})
@@ -288,7 +327,7 @@ func (h *Handler) AnswerQuestion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stdres.Writer(w, stdres.Response{
+	stdres.Writer(w, r, stdres.Response{
 		Code: http.StatusOK,
 		Info: "success",
 		Data: map[string]interface{}{"doc": answer},
@@ -301,7 +340,7 @@ func (h *Handler) DeleteQuestion(w http.ResponseWriter, r *http.Request) {
 
 	identity, err := identifier.GetFromContext(r.Context())
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusUnauthorized,
 			Info: err.Error(),
 		})
@@ -316,7 +355,7 @@ func (h *Handler) DeleteQuestion(w http.ResponseWriter, r *http.Request) {
 
 	err = h.svc.DeleteQuestion(ctx, input)
 	if errors.Is(err, ErrNotTheAuthor) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusUnauthorized,
 			Info: err.Error(),
 		})
@@ -325,7 +364,7 @@ func (h *Handler) DeleteQuestion(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if errors.Is(err, ErrQuestionNotFound) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusNotFound,
 			Info: err.Error(),
 		})
@@ -334,7 +373,7 @@ func (h *Handler) DeleteQuestion(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusInternalServerError,
 			Info: err.Error(),
 		})
@@ -345,7 +384,7 @@ func (h *Handler) DeleteQuestion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stdres.Writer(w, stdres.Response{
+	stdres.Writer(w, r, stdres.Response{
 		Code: http.StatusOK,
 		Info: "success",
 	})
@@ -362,7 +401,7 @@ func (h *Handler) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
 
 	identity, err := identifier.GetFromContext(r.Context())
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusUnauthorized,
 			Info: err.Error(),
 		})
@@ -372,7 +411,7 @@ func (h *Handler) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
 
 	err = json.NewDecoder(r.Body).Decode(&payload)
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusBadRequest,
 			Info: "failed decode payload",
 		})
@@ -387,7 +426,7 @@ func (h *Handler) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
 	})
 
 	if errors.Is(err, ErrQuestionNotFound) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusNotFound,
 			Info: err.Error(),
 		})
@@ -396,7 +435,7 @@ func (h *Handler) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if errors.Is(err, ErrNotTheAuthor) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusUnauthorized,
 			Info: err.Error(),
 		})
@@ -404,9 +443,18 @@ func (h *Handler) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if errors.Is(err, ErrQuestionLocked) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusLocked,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
 	vErr := validation.Errors{}
 	if errors.As(err, &vErr) {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusBadRequest,
 			Info: "validation error",
 			Data: vErr,
@@ -416,7 +464,7 @@ func (h *Handler) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		stdres.Writer(w, stdres.Response{
+		stdres.Writer(w, r, stdres.Response{
 			Code: http.StatusInternalServerError,
 			Info: err.Error(),
 		})
@@ -427,9 +475,630 @@ func (h *Handler) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stdres.Writer(w, stdres.Response{
+	stdres.Writer(w, r, stdres.Response{
 		Code: http.StatusOK,
 		Data: question,
 		Info: "success",
 	})
 }
+
+func (h *Handler) GetQuestionDetail(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "question.Handler.GetQuestionDetail")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	question, err := h.svc.GetQuestion(ctx, Input{
+		IdQuestion: chi.URLParam(r, "id"),
+		Identity:   *identity,
+	})
+
+	if errors.Is(err, ErrQuestionNotFound) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusNotFound,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while get question: %v", err))
+
+		return
+	}
+
+	if question.IsDuplicate() {
+		w.Header().Set("Location", fmt.Sprintf("/questions/%s", question.DuplicateOf.String))
+
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusFound,
+			Info: "question is a duplicate",
+			Data: map[string]interface{}{"doc": question},
+		})
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{"doc": question},
+	})
+}
+
+func (h *Handler) GetSimilarQuestions(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "question.Handler.GetSimilarQuestions")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	query, err := value.NewSimilarQuery(r.URL.Query())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "invalid query parameter",
+		})
+
+		return
+	}
+
+	result, err := h.svc.GetSimilarQuestions(ctx, Input{
+		Identity:     *identity,
+		SimilarQuery: query,
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while get similar questions: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{"docs": result},
+	})
+}
+
+func (h *Handler) MarkAsDuplicate(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "question.Handler.MarkAsDuplicate")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	var payload value.DuplicatePayload
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "failed decode payload",
+		})
+
+		return
+	}
+
+	question, err := h.svc.MarkAsDuplicate(ctx, Input{
+		IdQuestion:       chi.URLParam(r, "id"),
+		Identity:         *identity,
+		DuplicatePayload: payload,
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrNotModerator) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusForbidden,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrQuestionNotFound) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusNotFound,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrInvalidDuplicate) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusConflict,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while mark question as duplicate: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{"doc": question},
+	})
+}
+
+func (h *Handler) CloseQuestion(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "question.Handler.CloseQuestion")
+	defer span.End()
+
+	h.changeState(ctx, w, r, h.svc.CloseQuestion)
+}
+
+func (h *Handler) LockQuestion(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "question.Handler.LockQuestion")
+	defer span.End()
+
+	h.changeState(ctx, w, r, h.svc.LockQuestion)
+}
+
+func (h *Handler) ReopenQuestion(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "question.Handler.ReopenQuestion")
+	defer span.End()
+
+	h.changeState(ctx, w, r, h.svc.ReopenQuestion)
+}
+
+// changeState serves the close, lock and reopen endpoints, they only differ in
+// the service method that is called.
+func (h *Handler) changeState(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+	change func(context.Context, Input) (value.QuestionEntity, error),
+) {
+	span := trace.SpanFromContext(ctx)
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	var payload value.StatePayload
+
+	// reopen does not require a body
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			stdres.Writer(w, r, stdres.Response{
+				Code: http.StatusBadRequest,
+				Info: "failed decode payload",
+			})
+
+			return
+		}
+	}
+
+	question, err := change(ctx, Input{
+		IdQuestion:   chi.URLParam(r, "id"),
+		Identity:     *identity,
+		StatePayload: payload,
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrNotModerator) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusForbidden,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrQuestionNotFound) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusNotFound,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrQuestionIsOpen) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusConflict,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrQuestionLocked) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusLocked,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while change question state: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{"doc": question},
+	})
+}
+
+func (h *Handler) GetRelatedQuestions(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "question.Handler.GetRelatedQuestions")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	query, err := value.NewRelatedQuery(r.URL.Query())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "invalid query parameter",
+		})
+
+		return
+	}
+
+	result, err := h.svc.GetRelatedQuestions(ctx, Input{
+		IdQuestion:   chi.URLParam(r, "id"),
+		Identity:     *identity,
+		RelatedQuery: query,
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrQuestionNotFound) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusNotFound,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while get related questions: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{"docs": result},
+	})
+}
+
+func (h *Handler) GetAnswersOfQuestion(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "question.Handler.GetAnswersOfQuestion")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	query, err := value.NewAnswerQuery(r.URL.Query())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "invalid query parameter",
+		})
+
+		return
+	}
+
+	answers, err := h.svc.GetAnswers(ctx, Input{
+		Identity:    *identity,
+		AnswerQuery: query,
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if errors.Is(err, ErrQuestionNotFound) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusNotFound,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while get answers of question: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{"docs": answers},
+	})
+}
+
+func (h *Handler) FollowQuestion(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "question.Handler.FollowQuestion")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	err = h.svc.FollowQuestion(ctx, Input{
+		IdQuestion: chi.URLParam(r, "id"),
+		Identity:   *identity,
+	})
+	if errors.Is(err, ErrQuestionNotFound) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusNotFound,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while follow question: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+	})
+}
+
+func (h *Handler) UnfollowQuestion(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "question.Handler.UnfollowQuestion")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	err = h.svc.UnfollowQuestion(ctx, Input{
+		IdQuestion: chi.URLParam(r, "id"),
+		Identity:   *identity,
+	})
+	if errors.Is(err, ErrQuestionNotFound) || errors.Is(err, ErrNotFollowing) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusNotFound,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while unfollow question: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+	})
+}
+
+func (h *Handler) GetFollowedQuestions(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "question.Handler.GetFollowedQuestions")
+	defer span.End()
+
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	query, err := value.NewQuestionQuery(r.URL.Query())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "invalid query parameter",
+		})
+
+		return
+	}
+
+	questions, err := h.svc.GetFollowedQuestions(ctx, Input{
+		Identity:      *identity,
+		QuestionQuery: query,
+	})
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: err.Error(),
+		})
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while get followed questions: %v", err))
+
+		return
+	}
+
+	stdres.Writer(w, r, stdres.Response{
+		Code: http.StatusOK,
+		Info: "success",
+		Data: map[string]interface{}{
+			"docs": questions,
+		},
+	})
+}
@@ -2,46 +2,78 @@ package question
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/rizface/quora/cache"
+	"github.com/rizface/quora/idempotency"
 	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/ratelimit"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type Feature struct {
-	handler *Handler
-	r       *chi.Mux
+	handler     *Handler
+	r           *chi.Mux
+	limiter     *ratelimit.Limiter
+	idempotency *idempotency.Guard
+	Worker      *HotScoreWorker
 }
 
-func NewFeature(r *chi.Mux, db *sql.DB, tracer trace.Tracer) *Feature {
+func NewFeature(r *chi.Mux, db *sql.DB, c *cache.Cache, tracer trace.Tracer, limiter *ratelimit.Limiter, idempotency *idempotency.Guard) *Feature {
 	var (
-		questionRepo = NewRepository(db, tracer)
+		questionRepo = NewRepository(db, c, tracer)
 		voteRepo     = NewVoteRepository(db, tracer)
 		answerRepo   = NewAnswerRepo(db, tracer)
-		svc          = NewService(questionRepo, voteRepo, answerRepo, tracer)
+		relatedRepo  = NewRelatedRepo(db, tracer)
+		followRepo   = NewFollowRepo(db, tracer)
+		filter       = NewContentFilter(NewFilterRepo(db, tracer), tracer)
+		svc          = NewService(questionRepo, voteRepo, answerRepo, relatedRepo, followRepo, filter, c, tracer)
 		handler      = NewHandler(svc, tracer)
 	)
 
 	return &Feature{
-		handler: handler,
-		r:       r,
+		handler:     handler,
+		r:           r,
+		limiter:     limiter,
+		idempotency: idempotency,
+		Worker:      NewHotScoreWorker(questionRepo, tracer),
 	}
 }
 
 func (q *Feature) RegisterRoutes() {
 	q.r.Group(func(r chi.Router) {
 		r.Use(identifier.Identifier)
+		r.Use(q.limiter.Limit("questions", ratelimit.Rate{Limit: 300, Period: time.Minute}))
+
+		// posting has a tighter limit of its own against spam
+		// a replayed retry is not counted against it
+		posting := chi.Chain(
+			q.idempotency.Handle,
+			q.limiter.Limit("questions_post", ratelimit.Rate{Limit: 10, Period: time.Minute}),
+		)
 
 		r.Route("/questions", func(r chi.Router) {
-			r.Post("/", q.handler.CreateQuestion)
+			r.With(posting...).Post("/", q.handler.CreateQuestion)
 			r.Get("/", q.handler.GetQuestion)
+			r.Get("/similar", q.handler.GetSimilarQuestions)
+			r.Get("/{id}", q.handler.GetQuestionDetail)
+			r.Get("/{id}/related", q.handler.GetRelatedQuestions)
 			r.Delete("/{id}", q.handler.DeleteQuestion)
 			r.Put("/{id}", q.handler.UpdateQuestion)
+			r.Post("/{id}/duplicate", q.handler.MarkAsDuplicate)
+			r.Post("/{id}/close", q.handler.CloseQuestion)
+			r.Post("/{id}/lock", q.handler.LockQuestion)
+			r.Post("/{id}/reopen", q.handler.ReopenQuestion)
+			r.Post("/{id}/follow", q.handler.FollowQuestion)
+			r.Delete("/{id}/follow", q.handler.UnfollowQuestion)
 		})
 
+		r.Get("/me/following/questions", q.handler.GetFollowedQuestions)
+
 		r.Route("/answers", func(r chi.Router) {
-			r.Post("/", q.handler.AnswerQuestion)
-			// r.Get("/", q.Handler.GetAnswersOfQuestion) -> basically get all answers for specifict question, order by most upvoted
+			r.With(posting...).Post("/", q.handler.AnswerQuestion)
+			r.Get("/", q.handler.GetAnswersOfQuestion)
 			r.Patch("/{answerId}/vote", q.handler.Vote)
 		})
 	})
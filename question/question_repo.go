@@ -5,34 +5,77 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/lib/pq"
+	"github.com/rizface/quora/cache"
+	"github.com/rizface/quora/dataloader"
+	"github.com/rizface/quora/events"
 	"github.com/rizface/quora/question/value"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// questionOrders maps every supported sort to its ORDER BY expression, the
+// window of the "top" sort is applied in the WHERE clause.
+var questionOrders = map[string]string{
+	value.SortHot:    "q.hot_score DESC NULLS LAST, q.created_at DESC",
+	value.SortTop:    "stats.votes DESC NULLS LAST, q.created_at DESC",
+	value.SortNew:    "q.created_at DESC",
+	value.SortActive: "GREATEST(q.updated_at, stats.last_answer_at) DESC",
+}
+
 type Repository struct {
-	db     *sql.DB
-	tracer trace.Tracer
+	db        *sql.DB
+	cache     *cache.Cache
+	tracer    trace.Tracer
+	authorTTL time.Duration
 }
 
-func NewRepository(db *sql.DB, tracer trace.Tracer) *Repository {
+func NewRepository(db *sql.DB, c *cache.Cache, tracer trace.Tracer) *Repository {
 	return &Repository{
-		db:     db,
-		tracer: tracer,
+		db:        db,
+		cache:     c,
+		tracer:    tracer,
+		authorTTL: cache.TTL("author", 10*time.Minute),
 	}
 }
 
-func (r *Repository) Create(ctx context.Context, q value.QuestionEntity) error {
+func (r *Repository) Create(ctx context.Context, q value.QuestionEntity, e events.Event, moderation *value.ModerationEntry) error {
 	ctx, span := r.tracer.Start(ctx, "question.Repository.Create")
 	defer span.End()
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
 	query := `
-		INSERT INTO questions (id, author_id, space_id, question) VALUES($1, $2, $3, $4)
+		INSERT INTO questions (id, author_id, space_id, question, tags) VALUES($1, $2, $3, $4, $5)
 	`
 
-	_, err := r.db.ExecContext(ctx, query, q.Id, q.AuthorId, q.SpaceId, q.Question)
+	if _, err := tx.ExecContext(ctx, query, q.Id, q.AuthorId, q.SpaceId, q.Question, pq.Array(q.Tags)); err != nil {
+		return err
+	}
 
-	return err
+	// the asker follows the question to be notified of its answers
+	query = `
+		INSERT INTO question_followers (question_id, account_id) VALUES ($1, $2)
+	`
+
+	if _, err := tx.ExecContext(ctx, query, q.Id, q.AuthorId); err != nil {
+		return err
+	}
+
+	if err := queueModeration(ctx, tx, moderation); err != nil {
+		return err
+	}
+
+	if err := events.Record(ctx, tx, e); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *Repository) GetList(ctx context.Context, q value.QuestionQuery) ([]value.QuestionEntity, error) {
@@ -42,51 +85,39 @@ func (r *Repository) GetList(ctx context.Context, q value.QuestionQuery) ([]valu
 	var (
 		questions = []value.QuestionEntity{}
 		query     = `
-		with questions_answers as (
-			SELECT distinct on (q.question, a.updated_at, a.upvote)
-			q.id as question_id, 
-			q.author_id, q.space_id, q.question, q.created_at, q.updated_at, 
-			a.id as answer_id, 
-			a.answer,a.upvote, a.downvote, 
-			a.created_at as answer_created_at, 
-			a.updated_at as answer_updated_at, 
-			ac.id, ac.username 
+			SELECT
+			q.id, q.author_id, q.space_id, q.question, q.tags, q.state, q.state_reason, q.created_at, q.updated_at,
+			a.id, a.answer, a.upvote, a.downvote, a.score, a.created_at, a.updated_at,
+			ac.id, ac.username
 			FROM questions q
-			INNER JOIN answers a ON a.question_id = q.id
+			INNER JOIN LATERAL (
+				SELECT * FROM answers WHERE question_id = q.id ORDER BY score DESC, updated_at DESC LIMIT 1
+			) a ON TRUE
 			INNER JOIN accounts ac ON ac.id = a.answerer_id
-			ORDER BY a.upvote DESC, a.updated_at DESC
-		)
-		
-		SELECT DISTINCT ON(question) * FROM questions_answers
+			LEFT JOIN LATERAL (
+				SELECT SUM(upvote - downvote) AS votes, MAX(updated_at) AS last_answer_at
+				FROM answers WHERE question_id = q.id
+			) stats ON TRUE
+			WHERE q.duplicate_of IS NULL
+			AND (cardinality($3::uuid[]) = 0 OR q.space_id = ANY($3::uuid[]))
+			AND ($4::timestamp IS NULL OR q.created_at >= $4::timestamp)
+			ORDER BY %s, q.id
+			LIMIT $1 OFFSET $2
 		`
+		windowStart = sql.NullTime{}
 	)
 
-	if len(q.SpaceIds) > 0 {
-		query = fmt.Sprintf("%s %s %s", query, "WHERE space_id IN", q.SpaceIds.ToSqlArray())
+	if start := q.WindowStart(time.Now()); !start.IsZero() {
+		windowStart = sql.NullTime{Time: start, Valid: true}
 	}
 
-	query = fmt.Sprintf("%s %s", query, "LIMIT $1 OFFSET $2")
+	query = fmt.Sprintf(query, questionOrders[q.Sort])
 
-	rows, err := r.db.QueryContext(ctx, query, q.Limit, q.Skip)
+	rows, err := r.db.QueryContext(ctx, query, q.Limit, q.Skip, pq.Array(q.SpaceIds), windowStart)
 	if err != nil {
 		return []value.QuestionEntity{}, err
 	}
-
-	var getAuthor = func(authorId string) (value.Author, error) {
-		var (
-			author = value.Author{}
-			query  = `SELECT id, username FROM accounts WHERE id = $1`
-		)
-
-		err := r.db.
-			QueryRowContext(ctx, query, authorId).
-			Scan(&author.Id, &author.Username)
-		if errors.Is(err, sql.ErrNoRows) {
-			return author, ErrAuthorNotFound
-		}
-
-		return author, err
-	}
+	defer rows.Close()
 
 	for rows.Next() {
 		question := value.QuestionEntity{}
@@ -96,12 +127,16 @@ func (r *Repository) GetList(ctx context.Context, q value.QuestionQuery) ([]valu
 			&question.AuthorId,
 			&question.SpaceId,
 			&question.Question,
+			pq.Array(&question.Tags),
+			&question.State,
+			&question.StateReason,
 			&question.CreatedAt,
 			&question.UpdatedAt,
 			&question.Answer.Id,
 			&question.Answer.Answer,
 			&question.Answer.Upvote,
 			&question.Answer.Downvote,
+			&question.Answer.Score,
 			&question.Answer.CreatedAt,
 			&question.Answer.UpdatedAt,
 			&question.Answer.Answerer.Id,
@@ -111,17 +146,69 @@ func (r *Repository) GetList(ctx context.Context, q value.QuestionQuery) ([]valu
 			return []value.QuestionEntity{}, err
 		}
 
-		question.Author, err = getAuthor(question.AuthorId)
-		if err != nil {
-			return []value.QuestionEntity{}, err
+		questions = append(questions, question)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []value.QuestionEntity{}, err
+	}
+
+	authorIds := make([]string, 0, len(questions))
+	for _, question := range questions {
+		authorIds = append(authorIds, question.AuthorId)
+	}
+
+	// one batch for the whole page instead of one query per question
+	authors, err := dataloader.New(r.GetAuthors).LoadMany(ctx, authorIds)
+	if err != nil {
+		return []value.QuestionEntity{}, err
+	}
+
+	for i := range questions {
+		author, ok := authors[questions[i].AuthorId]
+		if !ok {
+			return []value.QuestionEntity{}, ErrAuthorNotFound
 		}
 
-		questions = append(questions, question)
+		questions[i].Author = author
 	}
 
 	return questions, nil
 }
 
+// GetAuthors reads the authors by id through the cache, the ones that are not
+// cached are queried at once. Usernames never change so the authors are not
+// invalidated.
+func (r *Repository) GetAuthors(ctx context.Context, authorIds []string) (map[string]value.Author, error) {
+	ctx, span := r.tracer.Start(ctx, "question.Repository.GetAuthors")
+	defer span.End()
+
+	return cache.FetchMany(ctx, r.cache, authorIds, authorKey, r.authorTTL, func(ctx context.Context, authorIds []string) (map[string]value.Author, error) {
+		var (
+			authors = map[string]value.Author{}
+			query   = `SELECT id, username FROM accounts WHERE id = ANY($1::uuid[])`
+		)
+
+		rows, err := r.db.QueryContext(ctx, query, pq.Array(authorIds))
+		if err != nil {
+			return authors, err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var author value.Author
+
+			if err := rows.Scan(&author.Id, &author.Username); err != nil {
+				return map[string]value.Author{}, err
+			}
+
+			authors[author.Id] = author
+		}
+
+		return authors, rows.Err()
+	})
+}
+
 func (r *Repository) GetTotalQuestions(ctx context.Context) (int, error) {
 	ctx, span := r.tracer.Start(ctx, "question.Repository.GetTotalQuestions")
 	defer span.End()
@@ -147,7 +234,8 @@ func (r *Repository) GetOne(ctx context.Context, questionId string) (value.Quest
 	var (
 		question value.QuestionEntity
 		query    = `
-			SELECT id, author_id, question, created_at, updated_at FROM questions WHERE id = $1
+			SELECT id, author_id, space_id, question, tags, duplicate_of, state, state_reason, created_at, updated_at
+			FROM questions WHERE id = $1
 		`
 	)
 
@@ -156,7 +244,12 @@ func (r *Repository) GetOne(ctx context.Context, questionId string) (value.Quest
 		Scan(
 			&question.Id,
 			&question.AuthorId,
+			&question.SpaceId,
 			&question.Question,
+			pq.Array(&question.Tags),
+			&question.DuplicateOf,
+			&question.State,
+			&question.StateReason,
 			&question.CreatedAt,
 			&question.UpdatedAt,
 		)
@@ -171,28 +264,295 @@ func (r *Repository) GetOne(ctx context.Context, questionId string) (value.Quest
 	return question, nil
 }
 
-func (r *Repository) DeleteQuestion(ctx context.Context, question value.QuestionEntity) error {
+func (r *Repository) DeleteQuestion(ctx context.Context, question value.QuestionEntity, e events.Event) error {
 	ctx, span := r.tracer.Start(ctx, "question.Repository.DeleteQuestion")
 	defer span.End()
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
 	command := `
 		DELETE FROM questions WHERE id = $1
 	`
 
-	_, err := r.db.ExecContext(ctx, command, question.Id)
+	if _, err := tx.ExecContext(ctx, command, question.Id); err != nil {
+		return err
+	}
 
-	return err
+	if err := events.Record(ctx, tx, e); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *Repository) UpdateQuestion(ctx context.Context, question value.QuestionEntity) error {
+func (r *Repository) UpdateQuestion(ctx context.Context, question value.QuestionEntity, e events.Event) error {
 	ctx, span := r.tracer.Start(ctx, "question.Repository.UpdateQuestion")
 	defer span.End()
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	command := `
+		UPDATE questions SET question = $1, space_id = $2, tags = $3 WHERE id = $4
+	`
+
+	_, err = tx.ExecContext(ctx, command, question.Question, question.SpaceId, pq.Array(question.Tags), question.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := events.Record(ctx, tx, e); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetSimilar(ctx context.Context, q value.SimilarQuery) ([]value.SimilarQuestion, error) {
+	ctx, span := r.tracer.Start(ctx, "question.Repository.GetSimilar")
+	defer span.End()
+
+	var (
+		questions = []value.SimilarQuestion{}
+		// % uses the pg_trgm similarity threshold, so the trigram index can be used
+		query = `
+			SELECT id, question, similarity(question, $1) AS score FROM questions
+			WHERE question % $1 AND id::text <> $2 AND duplicate_of IS NULL
+			ORDER BY score DESC
+			LIMIT $3
+		`
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, q.Text, q.ExcludeId, q.Limit)
+	if err != nil {
+		return questions, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		question := value.SimilarQuestion{}
+
+		if err := rows.Scan(&question.Id, &question.Question, &question.Similarity); err != nil {
+			return []value.SimilarQuestion{}, err
+		}
+
+		questions = append(questions, question)
+	}
+
+	return questions, rows.Err()
+}
+
+func (r *Repository) IsModerator(ctx context.Context, accountId string) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "question.Repository.IsModerator")
+	defer span.End()
+
+	var (
+		isModerator bool
+		query       = `
+			SELECT role IN ('moderator', 'admin') FROM accounts WHERE id = $1
+		`
+	)
+
+	err := r.db.QueryRowContext(ctx, query, accountId).Scan(&isModerator)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	return isModerator, err
+}
+
+func (r *Repository) MarkAsDuplicate(ctx context.Context, question, canonical value.QuestionEntity, moveAnswers bool, e events.Event) error {
+	ctx, span := r.tracer.Start(ctx, "question.Repository.MarkAsDuplicate")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	command := `
+		UPDATE questions SET duplicate_of = $1, state = $2, state_reason = $3, updated_at = $4 WHERE id = $5
+	`
+
+	_, err = tx.ExecContext(ctx, command,
+		canonical.Id, question.State, question.StateReason, question.UpdatedAt, question.Id,
+	)
+	if err != nil {
+		return err
+	}
+
+	// questions that were already closed as a duplicate of this one follow it to the canonical question
+	command = `
+		UPDATE questions SET duplicate_of = $1 WHERE duplicate_of = $2
+	`
+
+	if _, err := tx.ExecContext(ctx, command, canonical.Id, question.Id); err != nil {
+		return err
+	}
+
+	if moveAnswers {
+		command = `
+			UPDATE answers SET question_id = $1 WHERE question_id = $2
+		`
+
+		if _, err := tx.ExecContext(ctx, command, canonical.Id, question.Id); err != nil {
+			return err
+		}
+
+		if err := markHotScoreStale(ctx, tx, canonical.Id, question.Id); err != nil {
+			return err
+		}
+	}
+
+	if err := events.Record(ctx, tx, e); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) ChangeState(ctx context.Context, question value.QuestionEntity, e events.Event) error {
+	ctx, span := r.tracer.Start(ctx, "question.Repository.ChangeState")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	command := `
+		UPDATE questions SET state = $1, state_reason = $2, updated_at = $3 WHERE id = $4
+	`
+
+	_, err = tx.ExecContext(ctx, command, question.State, question.StateReason, question.UpdatedAt, question.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := events.Record(ctx, tx, e); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddReopenVote records the vote of one account to reopen the question and
+// returns the number of reopen votes the question has collected so far.
+func (r *Repository) AddReopenVote(ctx context.Context, question value.QuestionEntity, voterId string) (int, error) {
+	ctx, span := r.tracer.Start(ctx, "question.Repository.AddReopenVote")
+	defer span.End()
+
+	var (
+		total   int
+		command = `
+			INSERT INTO question_reopen_votes (question_id, voter_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+		`
+		query = `
+			SELECT COUNT(*) FROM question_reopen_votes WHERE question_id = $1
+		`
+	)
+
+	if _, err := r.db.ExecContext(ctx, command, question.Id, voterId); err != nil {
+		return total, err
+	}
+
+	if err := r.db.QueryRowContext(ctx, query, question.Id).Scan(&total); err != nil {
+		return total, err
+	}
+
+	return total, nil
+}
+
+func (r *Repository) Reopen(ctx context.Context, question value.QuestionEntity, e events.Event) error {
+	ctx, span := r.tracer.Start(ctx, "question.Repository.Reopen")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	command := `
+		UPDATE questions SET state = $1, state_reason = $2, duplicate_of = $3, updated_at = $4 WHERE id = $5
+	`
+
+	_, err = tx.ExecContext(ctx, command,
+		question.State, question.StateReason, question.DuplicateOf, question.UpdatedAt, question.Id,
+	)
+	if err != nil {
+		return err
+	}
+
+	command = `
+		DELETE FROM question_reopen_votes WHERE question_id = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, command, question.Id); err != nil {
+		return err
+	}
+
+	if err := events.Record(ctx, tx, e); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RefreshHotScores recomputes the hot score of the questions marked stale by a
+// change of their answers or votes. The score follows the reddit formula,
+// newer questions start higher and votes move them logarithmically, so a score
+// only has to be recomputed when the votes change. The stale questions are
+// locked while they are refreshed, a vote committed meanwhile marks its
+// question stale again once the refresh is done.
+func (r *Repository) RefreshHotScores(ctx context.Context, batch int) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "question.Repository.RefreshHotScores")
+	defer span.End()
+
+	command := `
+		WITH stale AS (
+			SELECT id, created_at FROM questions
+			WHERE hot_score_updated_at IS NULL
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), scored AS (
+			SELECT s.id, s.created_at, COALESCE(SUM(a.upvote - a.downvote), 0) + COUNT(a.id) AS score
+			FROM stale s
+			LEFT JOIN answers a ON a.question_id = s.id
+			GROUP BY s.id, s.created_at
+		)
+		UPDATE questions q SET
+			hot_score = SIGN(scored.score) * LOG(GREATEST(ABS(scored.score), 1))
+				+ (EXTRACT(EPOCH FROM scored.created_at) - 1134028003) / 45000,
+			hot_score_updated_at = CURRENT_TIMESTAMP
+		FROM scored WHERE q.id = scored.id
+	`
+
+	result, err := r.db.ExecContext(ctx, command, batch)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// markHotScoreStale makes the hot score worker recompute the score of the
+// questions with tx, once their answers or votes changed.
+func markHotScoreStale(ctx context.Context, tx *sql.Tx, questionIds ...string) error {
 	command := `
-		UPDATE questions SET question = $1, space_id = $2 WHERE id = $3
+		UPDATE questions SET hot_score_updated_at = NULL WHERE id = ANY($1)
 	`
 
-	_, err := r.db.ExecContext(ctx, command, question.Question, question.SpaceId, question.Id)
+	_, err := tx.ExecContext(ctx, command, pq.Array(questionIds))
 
 	return err
 }
@@ -0,0 +1,190 @@
+package question
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rizface/quora/question/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// weights of each signal when ranking related questions
+const (
+	relatedTagWeight        = 1.0
+	relatedSpaceWeight      = 0.5
+	relatedSimilarityWeight = 2.0
+
+	// the cache keeps more candidates than a client usually asks for, so
+	// different limits can be served from the same computation
+	relatedCandidates = 50
+)
+
+type RelatedRepo struct {
+	db     *sql.DB
+	tracer trace.Tracer
+	ttl    time.Duration
+}
+
+func NewRelatedRepo(db *sql.DB, tracer trace.Tracer) *RelatedRepo {
+	ttl, err := time.ParseDuration(os.Getenv("RELATED_QUESTIONS_TTL"))
+	if err != nil {
+		ttl = time.Hour
+	}
+
+	return &RelatedRepo{
+		db:     db,
+		tracer: tracer,
+		ttl:    ttl,
+	}
+}
+
+func scanRelated(rows *sql.Rows) ([]value.RelatedQuestion, error) {
+	defer rows.Close()
+
+	questions := []value.RelatedQuestion{}
+
+	for rows.Next() {
+		question := value.RelatedQuestion{}
+
+		err := rows.Scan(&question.Id, &question.Question, pq.Array(&question.Tags), &question.Score)
+		if err != nil {
+			return []value.RelatedQuestion{}, err
+		}
+
+		questions = append(questions, question)
+	}
+
+	return questions, rows.Err()
+}
+
+// GetCached returns the related questions computed within the TTL, fresh is
+// false when they have to be computed again. A fresh result may be empty.
+func (r *RelatedRepo) GetCached(ctx context.Context, question value.QuestionEntity, limit int) (related []value.RelatedQuestion, fresh bool, err error) {
+	ctx, span := r.tracer.Start(ctx, "question.RelatedRepo.GetCached")
+	defer span.End()
+
+	query := `
+		SELECT EXISTS(SELECT 1 FROM related_computations WHERE question_id = $1 AND computed_at > $2)
+	`
+
+	err = r.db.QueryRowContext(ctx, query, question.Id, time.Now().Add(-r.ttl)).Scan(&fresh)
+	if err != nil || !fresh {
+		return []value.RelatedQuestion{}, false, err
+	}
+
+	query = `
+		SELECT q.id, q.question, q.tags, rq.score FROM related_questions rq
+		INNER JOIN questions q ON q.id = rq.related_id
+		WHERE rq.question_id = $1 AND q.duplicate_of IS NULL
+		ORDER BY rq.score DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, question.Id, limit)
+	if err != nil {
+		return []value.RelatedQuestion{}, false, err
+	}
+
+	related, err = scanRelated(rows)
+
+	return related, err == nil, err
+}
+
+func (r *RelatedRepo) Compute(ctx context.Context, question value.QuestionEntity) ([]value.RelatedQuestion, error) {
+	ctx, span := r.tracer.Start(ctx, "question.RelatedRepo.Compute")
+	defer span.End()
+
+	query := `
+		SELECT q.id, q.question, q.tags,
+			cardinality(ARRAY(SELECT unnest(q.tags) INTERSECT SELECT unnest($2::text[]))) * $5::float8
+			+ CASE WHEN q.space_id = $3 THEN $6::float8 ELSE 0 END
+			+ similarity(q.question, $1) * $7::float8 AS score
+		FROM questions q
+		WHERE q.id <> $4 AND q.duplicate_of IS NULL
+			AND (q.tags && $2::text[] OR q.space_id = $3 OR q.question % $1)
+		ORDER BY score DESC
+		LIMIT $8
+	`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		question.Question,
+		pq.Array(question.Tags),
+		question.SpaceId,
+		question.Id,
+		relatedTagWeight,
+		relatedSpaceWeight,
+		relatedSimilarityWeight,
+		relatedCandidates,
+	)
+	if err != nil {
+		return []value.RelatedQuestion{}, err
+	}
+
+	return scanRelated(rows)
+}
+
+func (r *RelatedRepo) Store(ctx context.Context, question value.QuestionEntity, related []value.RelatedQuestion) error {
+	ctx, span := r.tracer.Start(ctx, "question.RelatedRepo.Store")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := time.Now()
+
+	// the row of the computation is locked until the commit, so concurrent
+	// misses of the same question replace the relations one after the other
+	command := `
+		INSERT INTO related_computations (question_id, computed_at) VALUES ($1, $2)
+		ON CONFLICT (question_id) DO UPDATE SET computed_at = EXCLUDED.computed_at
+	`
+
+	if _, err := tx.ExecContext(ctx, command, question.Id, now); err != nil {
+		return err
+	}
+
+	command = `
+		DELETE FROM related_questions WHERE question_id = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, command, question.Id); err != nil {
+		return err
+	}
+
+	command = `
+		INSERT INTO related_questions (question_id, related_id, score, computed_at) VALUES ($1, $2, $3, $4)
+	`
+
+	for _, rq := range related {
+		if _, err := tx.ExecContext(ctx, command, question.Id, rq.Id, rq.Score, now); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Invalidate drops every cached relation of the question, in both directions,
+// so that the next read computes them again from the edited question.
+func (r *RelatedRepo) Invalidate(ctx context.Context, question value.QuestionEntity) error {
+	ctx, span := r.tracer.Start(ctx, "question.RelatedRepo.Invalidate")
+	defer span.End()
+
+	command := `
+		WITH relations AS (
+			DELETE FROM related_questions WHERE question_id = $1 OR related_id = $1
+			RETURNING question_id
+		)
+		DELETE FROM related_computations WHERE question_id = $1 OR question_id IN (SELECT question_id FROM relations)
+	`
+
+	_, err := r.db.ExecContext(ctx, command, question.Id)
+
+	return err
+}
@@ -3,8 +3,15 @@ package question
 import (
 	"context"
 	"errors"
+	"log"
+	"os"
+	"strconv"
+	"time"
 
+	"github.com/rizface/quora/cache"
+	"github.com/rizface/quora/events"
 	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/metrics"
 	"github.com/rizface/quora/question/value"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -15,6 +22,14 @@ type (
 		repo       *Repository
 		voteRepo   *VoteRepo
 		answerRepo *AnswerRepo
+		filter     *ContentFilter
+		related    *RelatedRepo
+		followRepo *FollowRepo
+		cache      *cache.Cache
+		// number of community votes needed to reopen a closed question
+		reopenThreshold int
+		listTTL         time.Duration
+		questionTTL     time.Duration
 	}
 
 	AnwerQuestionRequest struct {
@@ -22,24 +37,55 @@ type (
 	}
 
 	Input struct {
-		IdQuestion      string
-		Identity        identifier.Claim
-		QuestionPayload value.QuestionPayload
-		QuestionQuery   value.QuestionQuery
-		VotePayload     value.VotePayload
-		AnswerPayload   value.AnswerPayload
+		IdQuestion       string
+		Identity         identifier.Claim
+		QuestionPayload  value.QuestionPayload
+		QuestionQuery    value.QuestionQuery
+		VotePayload      value.VotePayload
+		AnswerPayload    value.AnswerPayload
+		SimilarQuery     value.SimilarQuery
+		DuplicatePayload value.DuplicatePayload
+		StatePayload     value.StatePayload
+		RelatedQuery     value.RelatedQuery
+		AnswerQuery      value.AnswerQuery
 	}
 )
 
-func NewService(repo *Repository, voteRepo *VoteRepo, answerRepo *AnswerRepo, tracer trace.Tracer) *Service {
+func NewService(
+	repo *Repository,
+	voteRepo *VoteRepo,
+	answerRepo *AnswerRepo,
+	relatedRepo *RelatedRepo,
+	followRepo *FollowRepo,
+	filter *ContentFilter,
+	c *cache.Cache,
+	tracer trace.Tracer,
+) *Service {
 	return &Service{
 		repo:       repo,
 		voteRepo:   voteRepo,
 		answerRepo: answerRepo,
+		related:    relatedRepo,
+		followRepo: followRepo,
+		filter:     filter,
+		cache:      c,
 		tracer:     tracer,
+
+		reopenThreshold: reopenThreshold(),
+		listTTL:         cache.TTL("question_list", 30*time.Second),
+		questionTTL:     cache.TTL("question", 5*time.Minute),
 	}
 }
 
+func reopenThreshold() int {
+	threshold, err := strconv.Atoi(os.Getenv("QUESTION_REOPEN_VOTES"))
+	if err != nil || threshold < 1 {
+		return 3
+	}
+
+	return threshold
+}
+
 func (s *Service) CreateQuestion(ctx context.Context, input Input) (value.QuestionEntity, error) {
 	ctx, span := s.tracer.Start(ctx, "question.Service.CreateQuestion")
 	defer span.End()
@@ -53,10 +99,29 @@ func (s *Service) CreateQuestion(ctx context.Context, input Input) (value.Questi
 		return value.QuestionEntity{}, err
 	}
 
-	if err := s.repo.Create(ctx, question); err != nil {
+	content := value.NewQuestionContent(question)
+
+	verdict, err := s.filter.Check(ctx, content)
+	if err != nil {
+		return value.QuestionEntity{}, err
+	}
+
+	created := events.QuestionCreated{
+		QuestionId: question.Id,
+		AuthorId:   question.AuthorId,
+		SpaceId:    question.SpaceId.String,
+		Question:   question.Question,
+		Tags:       question.Tags,
+	}
+
+	if err := s.repo.Create(ctx, question, created, s.filter.Moderation(content, verdict)); err != nil {
 		return value.QuestionEntity{}, err
 	}
 
+	metrics.QuestionsCreated.Inc()
+
+	s.invalidate(ctx)
+
 	return question, nil
 }
 
@@ -68,20 +133,24 @@ func (s *Service) GetQuestions(ctx context.Context, input Input) (value.Aggregat
 		return value.Aggregate{}, err
 	}
 
-	questions, err := s.repo.GetList(ctx, input.QuestionQuery)
-	if err != nil {
-		return value.Aggregate{}, err
-	}
+	key := listKey(s.cache.Version(ctx, listNamespace), input.QuestionQuery)
 
-	totalQuestions, err := s.repo.GetTotalQuestions(ctx)
-	if err != nil {
-		return value.Aggregate{}, nil
-	}
+	return cache.Fetch(ctx, s.cache, key, s.listTTL, func(ctx context.Context) (value.Aggregate, error) {
+		questions, err := s.repo.GetList(ctx, input.QuestionQuery)
+		if err != nil {
+			return value.Aggregate{}, err
+		}
+
+		totalQuestions, err := s.repo.GetTotalQuestions(ctx)
+		if err != nil {
+			return value.Aggregate{}, err
+		}
 
-	return value.Aggregate{
-		Questions: questions,
-		Total:     totalQuestions,
-	}, nil
+		return value.Aggregate{
+			Questions: questions,
+			Total:     totalQuestions,
+		}, nil
+	})
 }
 
 func (s *Service) Vote(ctx context.Context, input Input) (value.Answer, error) {
@@ -102,6 +171,15 @@ func (s *Service) Vote(ctx context.Context, input Input) (value.Answer, error) {
 		return value.Answer{}, err
 	}
 
+	question, err := s.repo.GetOne(ctx, answer.QuestionId)
+	if err != nil {
+		return value.Answer{}, err
+	}
+
+	if question.IsLocked() {
+		return value.Answer{}, newStateError(question)
+	}
+
 	oldVote, err := s.voteRepo.GetOldVote(ctx, vote)
 	if err != nil && !errors.Is(err, ErrVoteNotFound) {
 		return value.Answer{}, err
@@ -121,10 +199,27 @@ func (s *Service) Vote(ctx context.Context, input Input) (value.Answer, error) {
 		}
 	}
 
-	if err := s.answerRepo.Vote(ctx, answer, vote); err != nil {
+	voted := events.AnswerVoted{
+		AnswerId:         answer.Id,
+		QuestionId:       question.Id,
+		QuestionAuthorId: question.AuthorId,
+		SpaceId:          question.SpaceId.String,
+		AnswererId:       answer.AnswererId,
+		VoterId:          vote.VoterId,
+		Type:             vote.Type,
+		Upvote:           answer.Upvote,
+		Downvote:         answer.Downvote,
+		Score:            answer.Score,
+	}
+
+	if err := s.answerRepo.Vote(ctx, answer, vote, voted); err != nil {
 		return value.Answer{}, err
 	}
 
+	metrics.Votes.WithLabelValues(vote.Type).Inc()
+
+	// votes are the most frequent write, the scores and the top answers shown
+	// in the lists are refreshed with the ttl of the lists instead
 	return answer, nil
 }
 
@@ -149,14 +244,38 @@ func (s *Service) Answer(ctx context.Context, input Input) (value.Answer, error)
 		return value.Answer{}, err
 	}
 
+	if question.IsClosed() || question.IsLocked() {
+		return value.Answer{}, newStateError(question)
+	}
+
+	content := value.NewAnswerContent(answer, question)
+
+	verdict, err := s.filter.Check(ctx, content)
+	if err != nil {
+		return value.Answer{}, err
+	}
+
 	answer, err = s.answerRepo.Create(ctx, CreateAnswerReq{
 		answer:   answer,
 		question: question,
+		event: events.AnswerCreated{
+			AnswerId:         answer.Id,
+			QuestionId:       question.Id,
+			QuestionAuthorId: question.AuthorId,
+			SpaceId:          question.SpaceId.String,
+			AnswererId:       answer.AnswererId,
+			Answer:           answer.Answer,
+		},
+		moderation: s.filter.Moderation(content, verdict),
 	})
 	if err != nil {
 		return value.Answer{}, err
 	}
 
+	metrics.AnswersCreated.Inc()
+
+	// like the votes, the answers only change what the lists show of a
+	// question, the lists catch up with their ttl
 	return answer, nil
 }
 
@@ -173,10 +292,18 @@ func (s *Service) DeleteQuestion(ctx context.Context, input Input) error {
 		return ErrNotTheAuthor
 	}
 
-	if err = s.repo.DeleteQuestion(ctx, question); err != nil {
+	deleted := events.QuestionDeleted{
+		QuestionId: question.Id,
+		AuthorId:   question.AuthorId,
+		SpaceId:    question.SpaceId.String,
+	}
+
+	if err := s.repo.DeleteQuestion(ctx, question, deleted); err != nil {
 		return err
 	}
 
+	s.invalidate(ctx, question.Id)
+
 	return nil
 }
 
@@ -193,6 +320,10 @@ func (s *Service) UpdateQuestion(ctx context.Context, input Input) (value.Questi
 		return value.QuestionEntity{}, ErrNotTheAuthor
 	}
 
+	if question.IsLocked() {
+		return value.QuestionEntity{}, newStateError(question)
+	}
+
 	question.SyncWithPayload(input.QuestionPayload)
 
 	err = question.Validate()
@@ -200,10 +331,321 @@ func (s *Service) UpdateQuestion(ctx context.Context, input Input) (value.Questi
 		return value.QuestionEntity{}, err
 	}
 
-	err = s.repo.UpdateQuestion(ctx, question)
+	updated := events.QuestionUpdated{
+		QuestionId: question.Id,
+		AuthorId:   question.AuthorId,
+		SpaceId:    question.SpaceId.String,
+		Question:   question.Question,
+		Tags:       question.Tags,
+	}
+
+	err = s.repo.UpdateQuestion(ctx, question, updated)
+	if err != nil {
+		return value.QuestionEntity{}, err
+	}
+
+	s.invalidate(ctx, question.Id)
+
+	// the update is committed, stale relations only last until their ttl
+	if err := s.related.Invalidate(ctx, question); err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		log.Printf("failed invalidate related questions of %s: %v", question.Id, err)
+	}
+
+	return question, nil
+}
+
+func (s *Service) GetQuestion(ctx context.Context, input Input) (value.QuestionEntity, error) {
+	ctx, span := s.tracer.Start(ctx, "question.Service.GetQuestion")
+	defer span.End()
+
+	return cache.Fetch(ctx, s.cache, questionKey(input.IdQuestion), s.questionTTL, func(ctx context.Context) (value.QuestionEntity, error) {
+		return s.repo.GetOne(ctx, input.IdQuestion)
+	})
+}
+
+// invalidate removes the questions and every page of the lists from the
+// cache, it is called once a question is written and committed. The write succeeded by
+// then, so a failing cache is only recorded and the stale entries expire with
+// their ttl.
+func (s *Service) invalidate(ctx context.Context, questionIds ...string) {
+	span := trace.SpanFromContext(ctx)
+
+	keys := make([]string, 0, len(questionIds))
+	for _, questionId := range questionIds {
+		keys = append(keys, questionKey(questionId))
+	}
+
+	if err := s.cache.Invalidate(ctx, keys...); err != nil {
+		span.RecordError(err)
+		log.Printf("failed invalidate cached questions %v: %v", questionIds, err)
+	}
+
+	if err := s.cache.Bump(ctx, listNamespace); err != nil {
+		span.RecordError(err)
+		log.Printf("failed invalidate cached question lists: %v", err)
+	}
+}
+
+func (s *Service) GetSimilarQuestions(ctx context.Context, input Input) ([]value.SimilarQuestion, error) {
+	ctx, span := s.tracer.Start(ctx, "question.Service.GetSimilarQuestions")
+	defer span.End()
+
+	if err := value.ValidateSimilarQuery(input.SimilarQuery); err != nil {
+		return []value.SimilarQuestion{}, err
+	}
+
+	return s.repo.GetSimilar(ctx, input.SimilarQuery)
+}
+
+func (s *Service) MarkAsDuplicate(ctx context.Context, input Input) (value.QuestionEntity, error) {
+	ctx, span := s.tracer.Start(ctx, "question.Service.MarkAsDuplicate")
+	defer span.End()
+
+	payload := input.DuplicatePayload
+
+	if err := value.ValidateDuplicatePayload(payload); err != nil {
+		return value.QuestionEntity{}, err
+	}
+
+	if err := s.ensureModerator(ctx, input.Identity); err != nil {
+		return value.QuestionEntity{}, err
+	}
+
+	question, err := s.repo.GetOne(ctx, input.IdQuestion)
+	if err != nil {
+		return value.QuestionEntity{}, err
+	}
+
+	canonical, err := s.repo.GetOne(ctx, payload.CanonicalId)
+	if err != nil {
+		return value.QuestionEntity{}, err
+	}
+
+	// always point to the end of the chain so readers are redirected only once
+	if canonical.IsDuplicate() {
+		canonical, err = s.repo.GetOne(ctx, canonical.DuplicateOf.String)
+		if err != nil {
+			return value.QuestionEntity{}, err
+		}
+	}
+
+	if canonical.Id == question.Id {
+		return value.QuestionEntity{}, ErrInvalidDuplicate
+	}
+
+	question.MarkAsDuplicate(canonical)
+
+	changed := stateChanged(question, input.Identity)
+
+	if err := s.repo.MarkAsDuplicate(ctx, question, canonical, payload.MoveAnswers, changed); err != nil {
+		return value.QuestionEntity{}, err
+	}
+
+	s.invalidate(ctx, question.Id)
+
+	return question, nil
+}
+
+func (s *Service) ensureModerator(ctx context.Context, identity identifier.Claim) error {
+	isModerator, err := s.repo.IsModerator(ctx, identity.AccountId)
+	if err != nil {
+		return err
+	}
+
+	if !isModerator {
+		return ErrNotModerator
+	}
+
+	return nil
+}
+
+func (s *Service) changeState(ctx context.Context, input Input, state string) (value.QuestionEntity, error) {
+	if err := value.ValidateStatePayload(input.StatePayload); err != nil {
+		return value.QuestionEntity{}, err
+	}
+
+	if err := s.ensureModerator(ctx, input.Identity); err != nil {
+		return value.QuestionEntity{}, err
+	}
+
+	question, err := s.repo.GetOne(ctx, input.IdQuestion)
+	if err != nil {
+		return value.QuestionEntity{}, err
+	}
+
+	question.ChangeState(state, input.StatePayload.Reason)
+
+	if err := s.repo.ChangeState(ctx, question, stateChanged(question, input.Identity)); err != nil {
+		return value.QuestionEntity{}, err
+	}
+
+	s.invalidate(ctx, question.Id)
+
+	return question, nil
+}
+
+func stateChanged(question value.QuestionEntity, actor identifier.Claim) events.QuestionStateChanged {
+	return events.QuestionStateChanged{
+		QuestionId:  question.Id,
+		AuthorId:    question.AuthorId,
+		ActorId:     actor.AccountId,
+		State:       question.State,
+		Reason:      question.StateReason.String,
+		DuplicateOf: question.DuplicateOf.String,
+	}
+}
+
+func (s *Service) CloseQuestion(ctx context.Context, input Input) (value.QuestionEntity, error) {
+	ctx, span := s.tracer.Start(ctx, "question.Service.CloseQuestion")
+	defer span.End()
+
+	return s.changeState(ctx, input, value.QuestionStateClosed)
+}
+
+func (s *Service) LockQuestion(ctx context.Context, input Input) (value.QuestionEntity, error) {
+	ctx, span := s.tracer.Start(ctx, "question.Service.LockQuestion")
+	defer span.End()
+
+	return s.changeState(ctx, input, value.QuestionStateLocked)
+}
+
+// ReopenQuestion reopens the question right away when it is requested by a
+// moderator, otherwise the request is counted as a community vote and the
+// question is reopened once enough votes are collected. Locked questions can
+// only be reopened by a moderator.
+func (s *Service) ReopenQuestion(ctx context.Context, input Input) (value.QuestionEntity, error) {
+	ctx, span := s.tracer.Start(ctx, "question.Service.ReopenQuestion")
+	defer span.End()
+
+	question, err := s.repo.GetOne(ctx, input.IdQuestion)
 	if err != nil {
 		return value.QuestionEntity{}, err
 	}
 
+	if !question.IsClosed() && !question.IsLocked() {
+		return value.QuestionEntity{}, ErrQuestionIsOpen
+	}
+
+	err = s.ensureModerator(ctx, input.Identity)
+	if err != nil && !errors.Is(err, ErrNotModerator) {
+		return value.QuestionEntity{}, err
+	}
+
+	if errors.Is(err, ErrNotModerator) {
+		if question.IsLocked() {
+			return value.QuestionEntity{}, newStateError(question)
+		}
+
+		votes, err := s.repo.AddReopenVote(ctx, question, input.Identity.AccountId)
+		if err != nil {
+			return value.QuestionEntity{}, err
+		}
+
+		if votes < s.reopenThreshold {
+			return question, nil
+		}
+	}
+
+	question.Reopen()
+
+	if err := s.repo.Reopen(ctx, question, stateChanged(question, input.Identity)); err != nil {
+		return value.QuestionEntity{}, err
+	}
+
+	s.invalidate(ctx, question.Id)
+
 	return question, nil
 }
+
+// GetRelatedQuestions serves the related questions from the cache and computes
+// them again when the cache is empty or expired.
+func (s *Service) GetRelatedQuestions(ctx context.Context, input Input) ([]value.RelatedQuestion, error) {
+	ctx, span := s.tracer.Start(ctx, "question.Service.GetRelatedQuestions")
+	defer span.End()
+
+	if err := value.ValidateRelatedQuery(input.RelatedQuery); err != nil {
+		return []value.RelatedQuestion{}, err
+	}
+
+	question, err := s.repo.GetOne(ctx, input.IdQuestion)
+	if err != nil {
+		return []value.RelatedQuestion{}, err
+	}
+
+	limit := input.RelatedQuery.Limit
+
+	related, fresh, err := s.related.GetCached(ctx, question, limit)
+	if err != nil {
+		return []value.RelatedQuestion{}, err
+	}
+
+	if fresh {
+		return related, nil
+	}
+
+	related, err = s.related.Compute(ctx, question)
+	if err != nil {
+		return []value.RelatedQuestion{}, err
+	}
+
+	if err := s.related.Store(ctx, question, related); err != nil {
+		return []value.RelatedQuestion{}, err
+	}
+
+	if len(related) > limit {
+		related = related[:limit]
+	}
+
+	return related, nil
+}
+
+func (s *Service) GetAnswers(ctx context.Context, input Input) ([]value.Answer, error) {
+	ctx, span := s.tracer.Start(ctx, "question.Service.GetAnswers")
+	defer span.End()
+
+	if err := value.ValidateAnswerQuery(input.AnswerQuery); err != nil {
+		return []value.Answer{}, err
+	}
+
+	if _, err := s.repo.GetOne(ctx, input.AnswerQuery.QuestionId); err != nil {
+		return []value.Answer{}, err
+	}
+
+	return s.answerRepo.GetList(ctx, input.AnswerQuery)
+}
+
+func (s *Service) FollowQuestion(ctx context.Context, input Input) error {
+	ctx, span := s.tracer.Start(ctx, "question.Service.FollowQuestion")
+	defer span.End()
+
+	question, err := s.repo.GetOne(ctx, input.IdQuestion)
+	if err != nil {
+		return err
+	}
+
+	return s.followRepo.Follow(ctx, question, input.Identity.AccountId)
+}
+
+func (s *Service) UnfollowQuestion(ctx context.Context, input Input) error {
+	ctx, span := s.tracer.Start(ctx, "question.Service.UnfollowQuestion")
+	defer span.End()
+
+	question, err := s.repo.GetOne(ctx, input.IdQuestion)
+	if err != nil {
+		return err
+	}
+
+	return s.followRepo.Unfollow(ctx, question, input.Identity.AccountId)
+}
+
+func (s *Service) GetFollowedQuestions(ctx context.Context, input Input) ([]value.QuestionEntity, error) {
+	ctx, span := s.tracer.Start(ctx, "question.Service.GetFollowedQuestions")
+	defer span.End()
+
+	if err := value.ValidateQuestionQueery(input.QuestionQuery); err != nil {
+		return []value.QuestionEntity{}, err
+	}
+
+	return s.followRepo.GetFollowed(ctx, input.Identity.AccountId, input.QuestionQuery)
+}
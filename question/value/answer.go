@@ -1,6 +1,7 @@
 package value
 
 import (
+	"math"
 	"strings"
 	"time"
 
@@ -27,6 +28,7 @@ type (
 		AnswererId string    `json:"answererId,omitempty"`
 		Upvote     int       `json:"upvote"`
 		Downvote   int       `json:"downvote"`
+		Score      float64   `json:"score"`
 		Answerer   Answerer  `json:"answerer"`
 		CreatedAt  time.Time `json:"created_at"`
 		UpdatedAt  time.Time `json:"updated_at"`
@@ -73,5 +75,23 @@ func (q *Answer) Vote(vote Vote, oldVote Vote) {
 		}
 	}
 
+	q.Score = WilsonScore(q.Upvote, q.Downvote)
 	q.UpdatedAt = time.Now()
 }
+
+// WilsonScore is the lower bound of the wilson score confidence interval for
+// the ratio of upvotes, with 95% confidence. Unlike the raw upvote count it
+// accounts for downvotes and for how many votes the answer has received.
+func WilsonScore(upvote, downvote int) float64 {
+	n := float64(upvote + downvote)
+	if n <= 0 {
+		return 0
+	}
+
+	var (
+		z    = 1.96
+		phat = float64(upvote) / n
+	)
+
+	return (phat + z*z/(2*n) - z*math.Sqrt((phat*(1-phat)+z*z/(4*n))/n)) / (1 + z*z/n)
+}
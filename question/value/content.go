@@ -0,0 +1,143 @@
+package value
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rizface/quora/nuller"
+)
+
+const (
+	ContentTypeQuestion = "question"
+	ContentTypeAnswer   = "answer"
+)
+
+const (
+	VerdictAllow    = "allow"
+	VerdictReject   = "reject"
+	VerdictModerate = "moderate"
+)
+
+type (
+	// Content is the text submitted by a user that must pass the content filters
+	// before it is persisted.
+	Content struct {
+		Id       string
+		Type     string
+		AuthorId string
+		SpaceId  nuller.NullString
+		Text     string
+	}
+
+	FilterResult struct {
+		Verdict string
+		Reason  string
+	}
+
+	FilterConfig struct {
+		BannedWords       []string
+		MaxLinks          int
+		MaxUppercaseRatio float64
+		MaxRepeatedChars  int
+		DuplicateWindow   int
+	}
+
+	ModerationEntry struct {
+		Id          string
+		ContentType string
+		ContentId   string
+		AuthorId    string
+		SpaceId     nuller.NullString
+		Reason      string
+	}
+)
+
+func NewQuestionContent(q QuestionEntity) Content {
+	return Content{
+		Id:       q.Id,
+		Type:     ContentTypeQuestion,
+		AuthorId: q.AuthorId,
+		SpaceId:  q.SpaceId,
+		Text:     q.Question,
+	}
+}
+
+func NewAnswerContent(a Answer, q QuestionEntity) Content {
+	return Content{
+		Id:       a.Id,
+		Type:     ContentTypeAnswer,
+		AuthorId: a.AnswererId,
+		SpaceId:  q.SpaceId,
+		Text:     a.Answer,
+	}
+}
+
+func NewModerationEntry(c Content, r FilterResult) ModerationEntry {
+	return ModerationEntry{
+		Id:          uuid.NewString(),
+		ContentType: c.Type,
+		ContentId:   c.Id,
+		AuthorId:    c.AuthorId,
+		SpaceId:     c.SpaceId,
+		Reason:      r.Reason,
+	}
+}
+
+func Allow() FilterResult {
+	return FilterResult{Verdict: VerdictAllow}
+}
+
+func Reject(reason string) FilterResult {
+	return FilterResult{Verdict: VerdictReject, Reason: reason}
+}
+
+func Moderate(reason string) FilterResult {
+	return FilterResult{Verdict: VerdictModerate, Reason: reason}
+}
+
+func (r FilterResult) IsRejected() bool {
+	return r.Verdict == VerdictReject
+}
+
+func (r FilterResult) NeedsModeration() bool {
+	return r.Verdict == VerdictModerate
+}
+
+// DefaultFilterConfig is used for content outside of any space and for spaces
+// that have not configured their own filters.
+func DefaultFilterConfig() FilterConfig {
+	config := FilterConfig{
+		MaxLinks:          3,
+		MaxUppercaseRatio: 0.7,
+		MaxRepeatedChars:  6,
+		DuplicateWindow:   5,
+	}
+
+	if words := os.Getenv("CONTENT_FILTER_BANNED_WORDS"); words != "" {
+		for _, word := range strings.Split(words, ",") {
+			if word = strings.TrimSpace(word); word != "" {
+				config.BannedWords = append(config.BannedWords, word)
+			}
+		}
+	}
+
+	if v, err := strconv.Atoi(os.Getenv("CONTENT_FILTER_MAX_LINKS")); err == nil {
+		config.MaxLinks = v
+	}
+
+	if v, err := strconv.ParseFloat(os.Getenv("CONTENT_FILTER_MAX_UPPERCASE_RATIO"), 64); err == nil {
+		config.MaxUppercaseRatio = v
+	}
+
+	if v, err := strconv.Atoi(os.Getenv("CONTENT_FILTER_MAX_REPEATED_CHARS")); err == nil {
+		config.MaxRepeatedChars = v
+	}
+
+	if v, err := strconv.Atoi(os.Getenv("CONTENT_FILTER_DUPLICATE_WINDOW")); err == nil {
+		config.DuplicateWindow = v
+	}
+
+	return config
+}
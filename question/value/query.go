@@ -1,12 +1,24 @@
 package value
 
 import (
-	"fmt"
 	"net/url"
 	"strconv"
-	"strings"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+const (
+	SortHot    = "hot"
+	SortTop    = "top"
+	SortNew    = "new"
+	SortActive = "active"
+
+	WindowDay   = "day"
+	WindowWeek  = "week"
+	WindowMonth = "month"
+	WindowAll   = "all"
 )
 
 type (
@@ -15,38 +27,107 @@ type (
 		Limit    int
 		Skip     int
 		SpaceIds StringIds
+		Sort     string
+		// Window limits the "top" sort to questions created within the period
+		Window string
 	}
 )
 
-func (s StringIds) ToSqlArray() string {
-	if len(s) < 1 {
-		return "()"
+func NewQuestionQuery(url url.Values) (QuestionQuery, error) {
+	q := QuestionQuery{
+		Skip:   0,
+		Limit:  20,
+		Sort:   SortHot,
+		Window: WindowAll,
 	}
 
-	ids := ""
+	if url.Has("skip") && url.Get("skip") != "" {
+		skip, err := strconv.Atoi(url.Get("skip"))
+		if err != nil {
+			return QuestionQuery{}, err
+		}
+
+		q.Skip = skip
+	}
 
-	for k, v := range s {
-		ids = fmt.Sprintf("%s'%s'", ids, v)
-		if k != len(s)-1 {
-			ids = fmt.Sprintf("%s, ", ids)
+	if url.Has("limit") && url.Get("limit") != "" {
+		limit, err := strconv.Atoi(url.Get("limit"))
+		if err != nil {
+			return QuestionQuery{}, err
 		}
+
+		q.Limit = limit
 	}
 
-	ids = fmt.Sprintf("(%s)", strings.Trim(ids, " "))
+	if url.Has("space_ids") && len(url.Get("space_ids")) > 0 {
+		q.SpaceIds = url["space_ids"]
+	}
 
-	return ids
+	if url.Get("sort") != "" {
+		q.Sort = url.Get("sort")
+	}
+
+	if url.Get("window") != "" {
+		q.Window = url.Get("window")
+	}
+
+	return q, nil
 }
 
-func NewQuestionQuery(url url.Values) (QuestionQuery, error) {
-	q := QuestionQuery{
-		Skip:  0,
-		Limit: 20,
+func ValidateQuestionQueery(q QuestionQuery) error {
+	return validation.Errors{
+		"skip":     validation.Validate(q.Skip, validation.Min(0)),
+		"limit":    validation.Validate(q.Limit, validation.Min(1)),
+		"spaceIds": validation.Validate(q.SpaceIds, validation.Each(is.UUID)),
+		"sort":     validation.Validate(q.Sort, validation.In(SortHot, SortTop, SortNew, SortActive)),
+		"window":   validation.Validate(q.Window, validation.In(WindowDay, WindowWeek, WindowMonth, WindowAll)),
+	}.Filter()
+}
+
+// WindowStart returns the oldest creation time included in the window, the
+// zero time means there is no lower bound. Only the "top" sort is windowed.
+func (q QuestionQuery) WindowStart(now time.Time) time.Time {
+	if q.Sort != SortTop {
+		return time.Time{}
+	}
+
+	switch q.Window {
+	case WindowDay:
+		return now.AddDate(0, 0, -1)
+	case WindowWeek:
+		return now.AddDate(0, 0, -7)
+	case WindowMonth:
+		return now.AddDate(0, -1, 0)
+	}
+
+	return time.Time{}
+}
+
+const (
+	AnswerSortScore  = "score"
+	AnswerSortUpvote = "upvote"
+	AnswerSortNew    = "new"
+)
+
+type AnswerQuery struct {
+	QuestionId string
+	Limit      int
+	Skip       int
+	Sort       string
+}
+
+func NewAnswerQuery(url url.Values) (AnswerQuery, error) {
+	q := AnswerQuery{
+		QuestionId: url.Get("questionId"),
+		Skip:       0,
+		Limit:      20,
+		Sort:       AnswerSortScore,
 	}
 
 	if url.Has("skip") && url.Get("skip") != "" {
 		skip, err := strconv.Atoi(url.Get("skip"))
 		if err != nil {
-			return QuestionQuery{}, err
+			return AnswerQuery{}, err
 		}
 
 		q.Skip = skip
@@ -55,22 +136,24 @@ func NewQuestionQuery(url url.Values) (QuestionQuery, error) {
 	if url.Has("limit") && url.Get("limit") != "" {
 		limit, err := strconv.Atoi(url.Get("limit"))
 		if err != nil {
-			return QuestionQuery{}, err
+			return AnswerQuery{}, err
 		}
 
 		q.Limit = limit
 	}
 
-	if url.Has("space_ids") && len(url.Get("space_ids")) > 0 {
-		q.SpaceIds = url["space_ids"]
+	if url.Get("sort") != "" {
+		q.Sort = url.Get("sort")
 	}
 
 	return q, nil
 }
 
-func ValidateQuestionQueery(q QuestionQuery) error {
+func ValidateAnswerQuery(q AnswerQuery) error {
 	return validation.Errors{
-		"skip":  validation.Validate(q.Skip, validation.Min(0)),
-		"limit": validation.Validate(q.Limit, validation.Min(1)),
+		"questionId": validation.Validate(q.QuestionId, validation.Required, is.UUID),
+		"skip":       validation.Validate(q.Skip, validation.Min(0)),
+		"limit":      validation.Validate(q.Limit, validation.Min(1), validation.Max(100)),
+		"sort":       validation.Validate(q.Sort, validation.In(AnswerSortScore, AnswerSortUpvote, AnswerSortNew)),
 	}.Filter()
 }
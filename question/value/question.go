@@ -1,6 +1,8 @@
 package value
 
 import (
+	"database/sql"
+	"strings"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
@@ -10,9 +12,18 @@ import (
 	"github.com/rizface/quora/nuller"
 )
 
+const (
+	QuestionStateOpen   = "open"
+	QuestionStateClosed = "closed"
+	QuestionStateLocked = "locked"
+
+	duplicateReason = "duplicate"
+)
+
 type QuestionPayload struct {
 	SpaceId  nuller.NullString `json:"spaceId"`
 	Question string            `json:"question"`
+	Tags     []string          `json:"tags"`
 }
 
 type Author struct {
@@ -21,14 +32,18 @@ type Author struct {
 }
 
 type QuestionEntity struct {
-	Id        string            `json:"id"`
-	SpaceId   nuller.NullString `json:"spaceId"`
-	AuthorId  string            `json:"authorId"`
-	Question  string            `json:"question"`
-	Author    Author            `json:"author"`
-	Answer    Answer            `json:"answer"`
-	CreatedAt time.Time         `json:"createdAt"`
-	UpdatedAt time.Time         `json:"updatedAt"`
+	Id          string            `json:"id"`
+	SpaceId     nuller.NullString `json:"spaceId"`
+	AuthorId    string            `json:"authorId"`
+	Question    string            `json:"question"`
+	Tags        []string          `json:"tags"`
+	DuplicateOf nuller.NullString `json:"duplicateOf"`
+	State       string            `json:"state"`
+	StateReason nuller.NullString `json:"stateReason"`
+	Author      Author            `json:"author"`
+	Answer      Answer            `json:"answer"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
 }
 
 type Aggregate struct {
@@ -42,6 +57,8 @@ func NewQuestionEntity(p QuestionPayload, authorId string) QuestionEntity {
 		SpaceId:   p.SpaceId,
 		AuthorId:  authorId,
 		Question:  p.Question,
+		Tags:      normalizeTags(p.Tags),
+		State:     QuestionStateOpen,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -52,6 +69,10 @@ func (q QuestionEntity) Validate() error {
 		"authorId": validation.Validate(q.AuthorId, validation.Required, is.UUID),
 		"spaceId":  validation.Validate(q.SpaceId, is.UUID),
 		"question": validation.Validate(q.Question, validation.Required),
+		"tags": validation.Validate(q.Tags,
+			validation.Length(0, 5),
+			validation.Each(validation.Required, validation.Length(1, 30)),
+		),
 	}.Filter()
 }
 
@@ -62,4 +83,72 @@ func (q QuestionEntity) IsThisTheAuthor(identity identifier.Claim) bool {
 func (q *QuestionEntity) SyncWithPayload(payload QuestionPayload) {
 	q.Question = payload.Question
 	q.SpaceId = payload.SpaceId
+	q.Tags = normalizeTags(payload.Tags)
+}
+
+// normalizeTags lowercases the tags and removes duplicates so that tags can be
+// compared with a plain equality in the database.
+func normalizeTags(tags []string) []string {
+	var (
+		normalized = []string{}
+		seen       = map[string]bool{}
+	)
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
+
+func (q QuestionEntity) IsDuplicate() bool {
+	return q.DuplicateOf.Valid
+}
+
+func (q *QuestionEntity) MarkAsDuplicate(canonical QuestionEntity) {
+	q.DuplicateOf = newNullString(canonical.Id)
+	q.ChangeState(QuestionStateClosed, duplicateReason)
+}
+
+func (q QuestionEntity) IsClosed() bool {
+	return q.State == QuestionStateClosed
+}
+
+func (q QuestionEntity) IsLocked() bool {
+	return q.State == QuestionStateLocked
+}
+
+func (q *QuestionEntity) ChangeState(state, reason string) {
+	q.State = state
+	q.StateReason = newNullString(reason)
+	q.UpdatedAt = time.Now()
+}
+
+// Reopen brings the question back to the open state, a question that was closed
+// as a duplicate is no longer redirected to its canonical question.
+func (q *QuestionEntity) Reopen() {
+	q.DuplicateOf = nuller.NullString{}
+	q.ChangeState(QuestionStateOpen, "")
+}
+
+func newNullString(s string) nuller.NullString {
+	return nuller.NullString{
+		NullString: sql.NullString{String: s, Valid: len(s) != 0},
+	}
+}
+
+type StatePayload struct {
+	Reason string `json:"reason"`
+}
+
+func ValidateStatePayload(p StatePayload) error {
+	return validation.Errors{
+		"reason": validation.Validate(p.Reason, validation.Required, validation.Length(1, 500)),
+	}.Filter()
 }
@@ -0,0 +1,44 @@
+package value
+
+import (
+	"net/url"
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+type (
+	RelatedQuestion struct {
+		Id       string   `json:"id"`
+		Question string   `json:"question"`
+		Tags     []string `json:"tags"`
+		Score    float64  `json:"score"`
+	}
+
+	RelatedQuery struct {
+		Limit int
+	}
+)
+
+func NewRelatedQuery(url url.Values) (RelatedQuery, error) {
+	q := RelatedQuery{
+		Limit: 10,
+	}
+
+	if url.Has("limit") && url.Get("limit") != "" {
+		limit, err := strconv.Atoi(url.Get("limit"))
+		if err != nil {
+			return RelatedQuery{}, err
+		}
+
+		q.Limit = limit
+	}
+
+	return q, nil
+}
+
+func ValidateRelatedQuery(q RelatedQuery) error {
+	return validation.Errors{
+		"limit": validation.Validate(q.Limit, validation.Min(1), validation.Max(50)),
+	}.Filter()
+}
@@ -0,0 +1,67 @@
+package value
+
+import (
+	"net/url"
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+type (
+	SimilarQuestion struct {
+		Id         string  `json:"id"`
+		Question   string  `json:"question"`
+		Similarity float64 `json:"similarity"`
+	}
+
+	SimilarQuery struct {
+		Text      string
+		ExcludeId string
+		Limit     int
+	}
+
+	DuplicatePayload struct {
+		CanonicalId string `json:"canonicalId"`
+		MoveAnswers bool   `json:"moveAnswers"`
+	}
+)
+
+func NewSimilarQuery(url url.Values) (SimilarQuery, error) {
+	q := SimilarQuery{
+		Text:  url.Get("q"),
+		Limit: 5,
+	}
+
+	if url.Has("limit") && url.Get("limit") != "" {
+		limit, err := strconv.Atoi(url.Get("limit"))
+		if err != nil {
+			return SimilarQuery{}, err
+		}
+
+		q.Limit = limit
+	}
+
+	return q, nil
+}
+
+func NewSimilarQueryFromQuestion(q QuestionEntity) SimilarQuery {
+	return SimilarQuery{
+		Text:      q.Question,
+		ExcludeId: q.Id,
+		Limit:     5,
+	}
+}
+
+func ValidateSimilarQuery(q SimilarQuery) error {
+	return validation.Errors{
+		"q":     validation.Validate(q.Text, validation.Required),
+		"limit": validation.Validate(q.Limit, validation.Min(1), validation.Max(20)),
+	}.Filter()
+}
+
+func ValidateDuplicatePayload(p DuplicatePayload) error {
+	return validation.Errors{
+		"canonicalId": validation.Validate(p.CanonicalId, validation.Required, is.UUID),
+	}.Filter()
+}
@@ -0,0 +1,65 @@
+package question
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/rizface/quora/periodic"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HotScoreWorker keeps the precomputed hot score of questions up to date, so
+// the question feed never computes the score per request.
+type HotScoreWorker struct {
+	repo   *Repository
+	tracer trace.Tracer
+	batch  int
+
+	loop *periodic.Worker
+}
+
+func NewHotScoreWorker(repo *Repository, tracer trace.Tracer) *HotScoreWorker {
+	interval, err := time.ParseDuration(os.Getenv("HOT_SCORE_REFRESH_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &HotScoreWorker{
+		repo:   repo,
+		tracer: tracer,
+		batch:  1000,
+		loop:   periodic.NewWorker(interval),
+	}
+}
+
+func (w *HotScoreWorker) Start() {
+	w.loop.Start(w.refresh)
+}
+
+// refresh keeps updating batches until every stale score is recomputed.
+func (w *HotScoreWorker) refresh(ctx context.Context) {
+	ctx, span := w.tracer.Start(ctx, "question.HotScoreWorker.refresh")
+	defer span.End()
+
+	for ctx.Err() == nil {
+		updated, err := w.repo.RefreshHotScores(ctx, w.batch)
+		if err != nil {
+			if ctx.Err() == nil {
+				span.RecordError(err)
+				log.Printf("failed refresh hot scores: %v", err)
+			}
+
+			return
+		}
+
+		if updated < int64(w.batch) {
+			return
+		}
+	}
+}
+
+func (w *HotScoreWorker) Stop(ctx context.Context) error {
+	return w.loop.Stop(ctx)
+}
@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/stdres"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Rate is a token bucket of Limit tokens that is refilled completely
+	// every Period, so bursts of up to Limit requests are allowed.
+	Rate struct {
+		Limit  int
+		Period time.Duration
+	}
+
+	Result struct {
+		Allowed   bool
+		Remaining int
+		// RetryAfter is how long until the next token, when not allowed
+		RetryAfter time.Duration
+		// Reset is how long until the bucket is full again
+		Reset time.Duration
+	}
+
+	// Store takes a token from the bucket of the key, creating a full
+	// bucket when the key is seen for the first time.
+	Store interface {
+		Take(ctx context.Context, key string, rate Rate) (Result, error)
+	}
+)
+
+// perMillisecond is the refill rate of the bucket
+func (r Rate) perMillisecond() float64 {
+	return float64(r.Limit) / float64(r.Period.Milliseconds())
+}
+
+// newResult describes the bucket once the token was taken, tokens is what is
+// left in the bucket.
+func newResult(rate Rate, allowed bool, tokens float64) Result {
+	result := Result{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration((float64(rate.Limit)-tokens)/rate.perMillisecond()) * time.Millisecond,
+	}
+
+	if !allowed {
+		result.RetryAfter = time.Duration(math.Ceil((1-tokens)/rate.perMillisecond())) * time.Millisecond
+	}
+
+	return result
+}
+
+// RateFromEnv reads the rate of a group from RATE_LIMIT_<GROUP> written as
+// "<limit>/<period>", for example RATE_LIMIT_LOGIN=5/1m, and falls back to
+// the given rate.
+func RateFromEnv(group string, fallback Rate) Rate {
+	limit, period, ok := strings.Cut(os.Getenv("RATE_LIMIT_"+strings.ToUpper(group)), "/")
+	if !ok {
+		return fallback
+	}
+
+	rate := Rate{}
+
+	rate.Limit, _ = strconv.Atoi(limit)
+	rate.Period, _ = time.ParseDuration(period)
+
+	if rate.Limit < 1 || rate.Period.Milliseconds() < 1 {
+		return fallback
+	}
+
+	return rate
+}
+
+type Limiter struct {
+	store  Store
+	tracer trace.Tracer
+}
+
+func NewLimiter(store Store, tracer trace.Tracer) *Limiter {
+	return &Limiter{
+		store:  store,
+		tracer: tracer,
+	}
+}
+
+// Limit throttles the routes of a group. Every account has a bucket of its own
+// in each group, requests without an identity share the bucket of their IP, so
+// the middleware keys by account only when it runs after identifier.Identifier.
+// The limits are a protection, when the store fails the request is let through.
+func (l *Limiter) Limit(group string, fallback Rate) func(http.Handler) http.Handler {
+	rate := RateFromEnv(group, fallback)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := l.tracer.Start(r.Context(), "ratelimit.Limiter.Limit")
+
+			key := fmt.Sprintf("ratelimit:%s:%s", group, identifier.ClientKey(r))
+
+			span.SetAttributes(attribute.String("group", group), attribute.String("key", key))
+
+			result, err := l.store.Take(ctx, key, rate)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, fmt.Sprintf("error while take token: %v", err))
+				span.End()
+
+				log.Printf("failed to rate limit %s: %v", key, err)
+
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			span.SetAttributes(attribute.Bool("allowed", result.Allowed))
+			span.End()
+
+			header := w.Header()
+			header.Set("X-RateLimit-Limit", strconv.Itoa(rate.Limit))
+			header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("X-RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+
+			if !result.Allowed {
+				header.Set("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+
+				stdres.Writer(w, r, stdres.Response{
+					Code: http.StatusTooManyRequests,
+					Info: "too many requests",
+				})
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
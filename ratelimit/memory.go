@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	period time.Duration
+}
+
+// MemoryStore keeps the buckets in the process, every instance limits on its
+// own. Buckets that are full again are swept at most once a minute.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]bucket
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]bucket{},
+	}
+}
+
+func (m *MemoryStore) Take(ctx context.Context, key string, rate Rate) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(m.lastSweep) >= time.Minute {
+		for key, b := range m.buckets {
+			if now.Sub(b.last) >= b.period {
+				delete(m.buckets, key)
+			}
+		}
+
+		m.lastSweep = now
+	}
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = bucket{tokens: float64(rate.Limit), last: now}
+	}
+
+	elapsed := float64(now.Sub(b.last).Milliseconds())
+
+	b.tokens = math.Min(float64(rate.Limit), b.tokens+elapsed*rate.perMillisecond())
+	b.last = now
+	b.period = rate.Period
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	m.buckets[key] = b
+
+	return newResult(rate, allowed, b.tokens), nil
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// take refills and takes from the bucket atomically with the clock of Redis,
+// so every instance of the app shares the same buckets. The bucket expires
+// once it would be full again.
+var take = redis.NewScript(`
+	local limit = tonumber(ARGV[1])
+	local period = tonumber(ARGV[2])
+
+	local time = redis.call('TIME')
+	local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+	local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'last')
+	local tokens = tonumber(bucket[1]) or limit
+	local last = tonumber(bucket[2]) or now
+
+	tokens = math.min(limit, tokens + math.max(0, now - last) * limit / period)
+
+	local allowed = 0
+	if tokens >= 1 then
+		tokens = tokens - 1
+		allowed = 1
+	end
+
+	redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'last', now)
+	redis.call('PEXPIRE', KEYS[1], period)
+
+	return {allowed, tostring(tokens)}
+`)
+
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{
+		client: client,
+	}
+}
+
+func (r *RedisStore) Take(ctx context.Context, key string, rate Rate) (Result, error) {
+	values, err := take.Run(ctx, r.client, []string{key}, rate.Limit, rate.Period.Milliseconds()).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(string)
+
+	tokens, err := strconv.ParseFloat(remaining, 64)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return newResult(rate, allowed == 1, tokens), nil
+}
@@ -0,0 +1,378 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/realtime/value"
+	"github.com/rizface/quora/stdres"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	maxCommandSize = 4096
+)
+
+type (
+	// Gateway serves the WebSocket clients. Every connection receives the
+	// notifications of its account and the messages of the questions it
+	// subscribed to, and reports whether it is viewing or answering them.
+	Gateway struct {
+		tracer       trace.Tracer
+		hub          *Hub
+		presence     *PresenceRepo
+		upgrader     websocket.Upgrader
+		pingInterval time.Duration
+
+		mu      sync.Mutex
+		conns   map[*Conn]struct{}
+		stopped bool
+		wg      sync.WaitGroup
+	}
+
+	Conn struct {
+		id        string
+		accountId string
+		ws        *websocket.Conn
+		client    *Client
+		// questions is only used by the read loop of the connection
+		questions map[string]struct{}
+	}
+)
+
+func NewGateway(hub *Hub, presence *PresenceRepo, pingInterval time.Duration, tracer trace.Tracer) *Gateway {
+	return &Gateway{
+		tracer:       tracer,
+		hub:          hub,
+		presence:     presence,
+		pingInterval: pingInterval,
+		conns:        map[*Conn]struct{}{},
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     checkOrigin(os.Getenv("WS_ALLOWED_ORIGINS")),
+		},
+	}
+}
+
+func wsPingInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("WS_PING_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	return interval
+}
+
+// checkOrigin accepts requests without an Origin header such as the ones of
+// mobile apps, browsers must come from the same host or an allowed origin.
+func checkOrigin(allowed string) func(r *http.Request) bool {
+	origins := map[string]bool{}
+	for _, origin := range strings.Split(allowed, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins[origin] = true
+		}
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" || origins["*"] || origins[origin] {
+			return true
+		}
+
+		return strings.TrimPrefix(strings.TrimPrefix(origin, "https://"), "http://") == r.Host
+	}
+}
+
+func (g *Gateway) ServeWS(w http.ResponseWriter, r *http.Request) {
+	identity, err := identifier.GetFromContext(r.Context())
+	if err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	g.mu.Lock()
+	if g.stopped {
+		g.mu.Unlock()
+
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusServiceUnavailable,
+			Info: "server is shutting down",
+		})
+
+		return
+	}
+	g.wg.Add(1)
+	g.mu.Unlock()
+
+	defer g.wg.Done()
+
+	// the upgrader writes the error response itself
+	ws, err := g.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	conn := &Conn{
+		id:        uuid.NewString(),
+		accountId: identity.AccountId,
+		ws:        ws,
+		client:    g.hub.Subscribe(value.AccountTopic(identity.AccountId)),
+		questions: map[string]struct{}{},
+	}
+
+	// Stop may have run during the upgrade, the connection is then closed by
+	// the write loop like the ones Stop found
+	g.mu.Lock()
+	if g.stopped {
+		g.hub.Unsubscribe(conn.client)
+	}
+	g.conns[conn] = struct{}{}
+	g.mu.Unlock()
+
+	writerDone := make(chan struct{})
+
+	go func() {
+		defer close(writerDone)
+
+		g.writeLoop(conn)
+	}()
+
+	g.readLoop(conn)
+
+	// stops the write loop if the read loop ended first
+	g.hub.Unsubscribe(conn.client)
+	<-writerDone
+
+	g.mu.Lock()
+	delete(g.conns, conn)
+	g.mu.Unlock()
+
+	g.leaveAll(conn)
+}
+
+func (g *Gateway) readLoop(conn *Conn) {
+	pongWait := 2 * g.pingInterval
+
+	conn.ws.SetReadLimit(maxCommandSize)
+	conn.ws.SetReadDeadline(time.Now().Add(pongWait)) //nolint:errcheck
+	conn.ws.SetPongHandler(func(string) error {
+		return conn.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := conn.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var command value.Command
+
+		if err := json.Unmarshal(data, &command); err != nil {
+			g.reply(conn, value.TypeError, "", map[string]string{"message": "invalid command"})
+
+			continue
+		}
+
+		g.handle(conn, command)
+	}
+}
+
+func (g *Gateway) handle(conn *Conn, command value.Command) {
+	ctx, span := g.tracer.Start(context.Background(), "realtime.Gateway.handle")
+	defer span.End()
+
+	err := value.ValidateCommand(command)
+
+	vErr := validation.Errors{}
+	if errors.As(err, &vErr) {
+		g.reply(conn, value.TypeError, command.QuestionId, map[string]interface{}{"doc": vErr})
+
+		return
+	}
+
+	switch command.Action {
+	case value.ActionPing:
+		g.reply(conn, value.TypePong, "", nil)
+
+		return
+	case value.ActionSubscribe:
+		g.hub.Join(conn.client, value.QuestionTopic(command.QuestionId))
+		conn.questions[command.QuestionId] = struct{}{}
+
+		err = g.presence.Set(ctx, conn.id, conn.accountId, command.QuestionId, value.ActivityViewing)
+	case value.ActionPresence:
+		if _, ok := conn.questions[command.QuestionId]; !ok {
+			g.reply(conn, value.TypeError, command.QuestionId, map[string]string{"message": "not subscribed"})
+
+			return
+		}
+
+		err = g.presence.Set(ctx, conn.id, conn.accountId, command.QuestionId, command.Activity)
+	case value.ActionUnsubscribe:
+		g.hub.Leave(conn.client, value.QuestionTopic(command.QuestionId))
+		delete(conn.questions, command.QuestionId)
+
+		err = g.presence.Remove(ctx, conn.id, command.QuestionId)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		g.reply(conn, value.TypeError, command.QuestionId, map[string]string{"message": "internal server error"})
+
+		return
+	}
+
+	switch command.Action {
+	case value.ActionSubscribe:
+		g.reply(conn, value.TypeSubscribed, command.QuestionId, nil)
+	case value.ActionUnsubscribe:
+		g.reply(conn, value.TypeUnsubscribed, command.QuestionId, nil)
+	}
+
+	g.broadcastPresence(ctx, command.QuestionId)
+}
+
+func (g *Gateway) reply(conn *Conn, messageType, questionId string, data interface{}) {
+	msg := value.Message{
+		Type:       messageType,
+		QuestionId: questionId,
+	}
+
+	if data != nil {
+		var err error
+
+		if msg, err = value.NewMessage(messageType, questionId, data); err != nil {
+			return
+		}
+	}
+
+	g.hub.Deliver(conn.client, msg)
+}
+
+func (g *Gateway) broadcastPresence(ctx context.Context, questionId string) {
+	presence, err := g.presence.Count(ctx, questionId)
+	if err != nil {
+		log.Printf("failed count presence of %s: %v", questionId, err)
+
+		return
+	}
+
+	msg, err := value.NewMessage(value.TypePresence, questionId, presence)
+	if err != nil {
+		return
+	}
+
+	if err := g.hub.Publish(ctx, msg); err != nil {
+		log.Printf("failed publish presence of %s: %v", questionId, err)
+	}
+}
+
+// leaveAll removes the presence of a closed connection, it runs after the
+// request is done so it can not use the request context.
+func (g *Gateway) leaveAll(conn *Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), writeWait)
+	defer cancel()
+
+	for questionId := range conn.questions {
+		if err := g.presence.Remove(ctx, conn.id, questionId); err != nil {
+			log.Printf("failed remove presence of %s: %v", questionId, err)
+
+			continue
+		}
+
+		g.broadcastPresence(ctx, questionId)
+	}
+}
+
+// writeLoop is the only writer of the connection. It closes the connection
+// when the client is unsubscribed, falls behind or stops answering pings.
+func (g *Gateway) writeLoop(conn *Conn) {
+	ticker := time.NewTicker(g.pingInterval)
+
+	defer func() {
+		ticker.Stop()
+		conn.ws.Close()
+	}()
+
+	for {
+		select {
+		case msg, ok := <-conn.client.Messages():
+			if !ok {
+				g.close(conn, websocket.CloseGoingAway, "server is shutting down")
+
+				return
+			}
+
+			conn.ws.SetWriteDeadline(time.Now().Add(writeWait)) //nolint:errcheck
+
+			if err := conn.ws.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-conn.client.Slow():
+			g.close(conn, websocket.CloseTryAgainLater, "client is too slow")
+
+			return
+		case <-ticker.C:
+			err := conn.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+			if err != nil {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), writeWait)
+			if err := g.presence.Touch(ctx, conn.id); err != nil {
+				log.Printf("failed refresh presence: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+func (g *Gateway) close(conn *Conn, code int, reason string) {
+	message := websocket.FormatCloseMessage(code, reason)
+
+	conn.ws.WriteControl(websocket.CloseMessage, message, time.Now().Add(writeWait)) //nolint:errcheck
+}
+
+// Stop sends a going away close frame to every connection and waits for them
+// to finish, connections are hijacked so the http server does not wait for
+// them on shutdown.
+func (g *Gateway) Stop(ctx context.Context) error {
+	g.mu.Lock()
+	g.stopped = true
+
+	for conn := range g.conns {
+		g.hub.Unsubscribe(conn.client)
+	}
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
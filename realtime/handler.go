@@ -0,0 +1,112 @@
+package realtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/realtime/value"
+	"github.com/rizface/quora/stdres"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Handler struct {
+	tracer trace.Tracer
+	hub    *Hub
+	// interval of the comments sent to keep idle connections open
+	keepAlive time.Duration
+}
+
+func NewHandler(hub *Hub, tracer trace.Tracer) *Handler {
+	keepAlive, err := time.ParseDuration(os.Getenv("SSE_KEEPALIVE_INTERVAL"))
+	if err != nil || keepAlive <= 0 {
+		keepAlive = 15 * time.Second
+	}
+
+	return &Handler{
+		tracer:    tracer,
+		hub:       hub,
+		keepAlive: keepAlive,
+	}
+}
+
+// Stream pushes the changes of a question as Server-Sent Events until the
+// client disconnects or the server stops.
+func (h *Handler) Stream(w http.ResponseWriter, r *http.Request) {
+	_, span := h.tracer.Start(r.Context(), "realtime.Handler.Stream")
+	defer span.End()
+
+	if _, err := identifier.GetFromContext(r.Context()); err != nil {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusUnauthorized,
+			Info: err.Error(),
+		})
+
+		return
+	}
+
+	query := value.NewStreamQuery(r.URL.Query())
+
+	vErr := validation.Errors{}
+	if err := value.ValidateStreamQuery(query); errors.As(err, &vErr) {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusBadRequest,
+			Info: "validation error",
+			Data: map[string]interface{}{"doc": vErr},
+		})
+
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		stdres.Writer(w, r, stdres.Response{
+			Code: http.StatusInternalServerError,
+			Info: "streaming is not supported",
+		})
+
+		return
+	}
+
+	client := h.hub.Subscribe(value.QuestionTopic(query.QuestionId))
+	defer h.hub.Unsubscribe(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(h.keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-client.Messages():
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(msg)
+			if err != nil {
+				span.RecordError(err)
+
+				continue
+			}
+
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.Type, data)
+			flusher.Flush()
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
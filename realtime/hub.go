@@ -0,0 +1,308 @@
+package realtime
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rizface/quora/realtime/value"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	questionChannel = "question_events"
+	// notified by a trigger on the notifications table
+	notificationChannel = "account_notifications"
+
+	// payloads of pg_notify must be shorter than 8000 bytes
+	maxPayload = 7999
+
+	// number of messages buffered for a client, messages for a client that
+	// does not keep up are dropped
+	clientBuffer = 32
+)
+
+type (
+	// Hub publishes messages through PostgreSQL NOTIFY and delivers the
+	// notifications it LISTENs to the clients of this instance, so a client
+	// receives the messages published by every instance.
+	Hub struct {
+		db     *sql.DB
+		dsn    string
+		tracer trace.Tracer
+
+		mu      sync.RWMutex
+		topics  map[string]map[*Client]struct{}
+		stopped bool
+
+		listener *pq.Listener
+		cancel   context.CancelFunc
+		wg       sync.WaitGroup
+	}
+
+	// Client receives the messages of the topics it joined, topics are only
+	// read and written with the lock of the hub held.
+	Client struct {
+		messages chan value.Message
+		topics   map[string]struct{}
+		closed   bool
+
+		slow     chan struct{}
+		slowOnce sync.Once
+	}
+)
+
+func NewHub(db *sql.DB, dsn string, tracer trace.Tracer) *Hub {
+	return &Hub{
+		db:     db,
+		dsn:    dsn,
+		tracer: tracer,
+		topics: map[string]map[*Client]struct{}{},
+	}
+}
+
+// Messages is closed when the client is unsubscribed or the hub is stopped.
+func (c *Client) Messages() <-chan value.Message {
+	return c.messages
+}
+
+// Slow is closed once a message was dropped because the buffer of the client
+// was full.
+func (c *Client) Slow() <-chan struct{} {
+	return c.slow
+}
+
+func (h *Hub) Subscribe(topics ...string) *Client {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	client := &Client{
+		messages: make(chan value.Message, clientBuffer),
+		topics:   map[string]struct{}{},
+		slow:     make(chan struct{}),
+	}
+
+	if h.stopped {
+		client.closed = true
+		close(client.messages)
+
+		return client
+	}
+
+	for _, topic := range topics {
+		h.join(client, topic)
+	}
+
+	return client
+}
+
+func (h *Hub) Join(client *Client, topic string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !client.closed {
+		h.join(client, topic)
+	}
+}
+
+func (h *Hub) join(client *Client, topic string) {
+	if h.topics[topic] == nil {
+		h.topics[topic] = map[*Client]struct{}{}
+	}
+
+	h.topics[topic][client] = struct{}{}
+	client.topics[topic] = struct{}{}
+}
+
+func (h *Hub) Leave(client *Client, topic string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.leave(client, topic)
+}
+
+func (h *Hub) leave(client *Client, topic string) {
+	delete(client.topics, topic)
+
+	clients, ok := h.topics[topic]
+	if !ok {
+		return
+	}
+
+	delete(clients, client)
+
+	if len(clients) == 0 {
+		delete(h.topics, topic)
+	}
+}
+
+func (h *Hub) Unsubscribe(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(client)
+}
+
+// remove must be called with the write lock held, the messages channel is only
+// closed here so it is never closed twice or written after being closed.
+func (h *Hub) remove(client *Client) {
+	if client.closed {
+		return
+	}
+
+	for topic := range client.topics {
+		h.leave(client, topic)
+	}
+
+	client.closed = true
+	close(client.messages)
+}
+
+// Deliver sends a message to a single client of this instance.
+func (h *Hub) Deliver(client *Client, msg value.Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if !client.closed {
+		h.send(client, msg)
+	}
+}
+
+// send must be called with the lock held.
+func (h *Hub) send(client *Client, msg value.Message) {
+	select {
+	case client.messages <- msg:
+	default:
+		client.slowOnce.Do(func() {
+			close(client.slow)
+		})
+	}
+}
+
+func (h *Hub) Publish(ctx context.Context, msg value.Message) error {
+	ctx, span := h.tracer.Start(ctx, "realtime.Hub.Publish")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("type", msg.Type))
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	if len(payload) > maxPayload {
+		msg.Data = nil
+
+		if payload, err = json.Marshal(msg); err != nil {
+			return err
+		}
+	}
+
+	_, err = h.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, questionChannel, string(payload))
+
+	return err
+}
+
+func (h *Hub) dispatch(payload string) {
+	var msg value.Message
+
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		log.Printf("failed decode realtime message: %v", err)
+
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.topics[msg.Topic()] {
+		h.send(client, msg)
+	}
+}
+
+func (h *Hub) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+
+	h.listener = pq.NewListener(h.dsn, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("realtime listener: %v", err)
+		}
+	})
+
+	for _, channel := range []string{questionChannel, notificationChannel} {
+		if err := h.listener.Listen(channel); err != nil {
+			log.Printf("failed listen to %s: %v", channel, err)
+		}
+	}
+
+	h.wg.Add(1)
+
+	go func() {
+		defer h.wg.Done()
+
+		ticker := time.NewTicker(90 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case n := <-h.listener.Notify:
+				// nil is sent after the connection is re-established,
+				// notifications sent while it was lost are not delivered
+				if n != nil {
+					h.dispatch(n.Extra)
+				}
+			case <-ticker.C:
+				if err := h.listener.Ping(); err != nil {
+					log.Printf("failed ping realtime listener: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop closes the listener and every client, so open streams return before
+// the server shuts down.
+func (h *Hub) Stop(ctx context.Context) error {
+	h.mu.Lock()
+	h.stopped = true
+
+	clients := map[*Client]struct{}{}
+	for _, topic := range h.topics {
+		for client := range topic {
+			clients[client] = struct{}{}
+		}
+	}
+
+	for client := range clients {
+		h.remove(client)
+	}
+	h.mu.Unlock()
+
+	if h.cancel == nil {
+		return nil
+	}
+
+	h.cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return h.listener.Close()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,101 @@
+package realtime
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rizface/quora/realtime/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PresenceRepo tracks which accounts have a question open over a WebSocket.
+// Rows are kept alive by the heartbeat of the connection, rows of connections
+// lost without a clean close expire after the ttl.
+type PresenceRepo struct {
+	db     *sql.DB
+	tracer trace.Tracer
+	ttl    time.Duration
+}
+
+func NewPresenceRepo(db *sql.DB, ttl time.Duration, tracer trace.Tracer) *PresenceRepo {
+	return &PresenceRepo{
+		db:     db,
+		tracer: tracer,
+		ttl:    ttl,
+	}
+}
+
+func (p *PresenceRepo) Set(ctx context.Context, connectionId, accountId, questionId, activity string) error {
+	ctx, span := p.tracer.Start(ctx, "realtime.PresenceRepo.Set")
+	defer span.End()
+
+	command := `
+		INSERT INTO question_presence (connection_id, question_id, account_id, activity, seen_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+		ON CONFLICT (connection_id, question_id) DO UPDATE SET activity = EXCLUDED.activity, seen_at = EXCLUDED.seen_at
+	`
+
+	_, err := p.db.ExecContext(ctx, command, connectionId, questionId, accountId, activity)
+
+	return err
+}
+
+func (p *PresenceRepo) Remove(ctx context.Context, connectionId, questionId string) error {
+	ctx, span := p.tracer.Start(ctx, "realtime.PresenceRepo.Remove")
+	defer span.End()
+
+	command := `
+		DELETE FROM question_presence WHERE connection_id = $1 AND question_id = $2
+	`
+
+	_, err := p.db.ExecContext(ctx, command, connectionId, questionId)
+
+	return err
+}
+
+func (p *PresenceRepo) Touch(ctx context.Context, connectionId string) error {
+	ctx, span := p.tracer.Start(ctx, "realtime.PresenceRepo.Touch")
+	defer span.End()
+
+	command := `
+		UPDATE question_presence SET seen_at = CURRENT_TIMESTAMP WHERE connection_id = $1
+	`
+
+	_, err := p.db.ExecContext(ctx, command, connectionId)
+
+	return err
+}
+
+// Count removes the expired rows of the question and counts the distinct
+// accounts per activity, an account with several tabs open is counted once.
+func (p *PresenceRepo) Count(ctx context.Context, questionId string) (value.Presence, error) {
+	ctx, span := p.tracer.Start(ctx, "realtime.PresenceRepo.Count")
+	defer span.End()
+
+	expiredBefore := time.Now().Add(-p.ttl)
+
+	command := `
+		DELETE FROM question_presence WHERE question_id = $1 AND seen_at < $2
+	`
+
+	if _, err := p.db.ExecContext(ctx, command, questionId, expiredBefore); err != nil {
+		return value.Presence{}, err
+	}
+
+	var (
+		presence = value.Presence{}
+		query    = `
+			SELECT
+				COUNT(DISTINCT account_id) FILTER (WHERE activity = $2),
+				COUNT(DISTINCT account_id) FILTER (WHERE activity = $3)
+			FROM question_presence WHERE question_id = $1
+		`
+	)
+
+	err := p.db.
+		QueryRowContext(ctx, query, questionId, value.ActivityViewing, value.ActivityAnswering).
+		Scan(&presence.Viewing, &presence.Answering)
+
+	return presence, err
+}
@@ -0,0 +1,74 @@
+package realtime
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/rizface/quora/events"
+	"github.com/rizface/quora/realtime/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Publisher pushes the question events that clients watch to the hub.
+type Publisher struct {
+	tracer trace.Tracer
+	hub    *Hub
+}
+
+func NewPublisher(hub *Hub, tracer trace.Tracer) *Publisher {
+	return &Publisher{
+		hub:    hub,
+		tracer: tracer,
+	}
+}
+
+// Register subscribes the publisher, the messages are only pushed to the
+// clients that are connected so they are not written with the transaction of
+// the bus. A message pushed again after a crash only refreshes the clients.
+func (p *Publisher) Register(bus *events.Bus) {
+	events.Subscribe(bus, "realtime.answer_created", p.OnAnswerCreated)
+	events.Subscribe(bus, "realtime.answer_voted", p.OnAnswerVoted)
+	events.Subscribe(bus, "realtime.question_updated", p.OnQuestionUpdated)
+}
+
+func (p *Publisher) publish(ctx context.Context, messageType, questionId string, data interface{}) error {
+	msg, err := value.NewMessage(messageType, questionId, data)
+	if err != nil {
+		return err
+	}
+
+	return p.hub.Publish(ctx, msg)
+}
+
+func (p *Publisher) OnAnswerCreated(ctx context.Context, _ *sql.Tx, answer events.AnswerCreated) error {
+	ctx, span := p.tracer.Start(ctx, "realtime.Publisher.OnAnswerCreated")
+	defer span.End()
+
+	return p.publish(ctx, value.TypeAnswerCreated, answer.QuestionId, map[string]interface{}{
+		"answerId":   answer.AnswerId,
+		"answererId": answer.AnswererId,
+		"answer":     answer.Answer,
+	})
+}
+
+func (p *Publisher) OnAnswerVoted(ctx context.Context, _ *sql.Tx, vote events.AnswerVoted) error {
+	ctx, span := p.tracer.Start(ctx, "realtime.Publisher.OnAnswerVoted")
+	defer span.End()
+
+	return p.publish(ctx, value.TypeVoteChanged, vote.QuestionId, map[string]interface{}{
+		"answerId": vote.AnswerId,
+		"upvote":   vote.Upvote,
+		"downvote": vote.Downvote,
+		"score":    vote.Score,
+	})
+}
+
+func (p *Publisher) OnQuestionUpdated(ctx context.Context, _ *sql.Tx, question events.QuestionUpdated) error {
+	ctx, span := p.tracer.Start(ctx, "realtime.Publisher.OnQuestionUpdated")
+	defer span.End()
+
+	return p.publish(ctx, value.TypeQuestionEdited, question.QuestionId, map[string]interface{}{
+		"question": question.Question,
+		"tags":     question.Tags,
+	})
+}
@@ -0,0 +1,47 @@
+package realtime
+
+import (
+	"database/sql"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rizface/quora/events"
+	"github.com/rizface/quora/identifier"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Feature struct {
+	handler *Handler
+	r       *chi.Mux
+	Hub     *Hub
+	Gateway *Gateway
+}
+
+// NewFeature needs the dsn of the database because LISTEN holds a dedicated
+// connection outside of the pool.
+func NewFeature(r *chi.Mux, db *sql.DB, dsn string, tracer trace.Tracer, bus *events.Bus) *Feature {
+	var (
+		hub          = NewHub(db, dsn, tracer)
+		handler      = NewHandler(hub, tracer)
+		pingInterval = wsPingInterval()
+		// presence outlives two missed pings
+		presence = NewPresenceRepo(db, 2*pingInterval+pingInterval/2, tracer)
+	)
+
+	NewPublisher(hub, tracer).Register(bus)
+
+	return &Feature{
+		handler: handler,
+		r:       r,
+		Hub:     hub,
+		Gateway: NewGateway(hub, presence, pingInterval, tracer),
+	}
+}
+
+func (f *Feature) RegisterRoutes() {
+	f.r.Group(func(r chi.Router) {
+		r.Use(identifier.QueryIdentifier)
+
+		r.Get("/stream", f.handler.Stream)
+		r.Get("/ws", f.Gateway.ServeWS)
+	})
+}
@@ -0,0 +1,47 @@
+package value
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+const (
+	ActionSubscribe   = "subscribe"
+	ActionUnsubscribe = "unsubscribe"
+	ActionPresence    = "presence"
+	ActionPing        = "ping"
+
+	ActivityViewing   = "viewing"
+	ActivityAnswering = "answering"
+)
+
+// Command is sent by WebSocket clients
+type Command struct {
+	Action     string `json:"action"`
+	QuestionId string `json:"questionId"`
+	Activity   string `json:"activity"`
+}
+
+func ValidateCommand(c Command) error {
+	var (
+		questionRules = []validation.Rule{validation.Required, is.UUID}
+		activityRules = []validation.Rule{validation.In(ActivityViewing, ActivityAnswering)}
+	)
+
+	if c.Action == ActionPing {
+		questionRules = []validation.Rule{}
+	}
+
+	if c.Action == ActionPresence {
+		activityRules = append(activityRules, validation.Required)
+	}
+
+	return validation.Errors{
+		"action": validation.Validate(c.Action,
+			validation.Required,
+			validation.In(ActionSubscribe, ActionUnsubscribe, ActionPresence, ActionPing),
+		),
+		"questionId": validation.Validate(c.QuestionId, questionRules...),
+		"activity":   validation.Validate(c.Activity, activityRules...),
+	}.Filter()
+}
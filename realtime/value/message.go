@@ -0,0 +1,85 @@
+package value
+
+import (
+	"encoding/json"
+	"net/url"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+const (
+	TypeAnswerCreated  = "answer-created"
+	TypeVoteChanged    = "vote-changed"
+	TypeQuestionEdited = "question-edited"
+	TypeNotification   = "notification"
+	TypePresence       = "presence"
+	TypeSubscribed     = "subscribed"
+	TypeUnsubscribed   = "unsubscribed"
+	TypePong           = "pong"
+	TypeError          = "error"
+)
+
+type (
+	// Message is pushed to the clients that watch the question. Data is left
+	// out when it does not fit into a notification, clients then fetch the
+	// changed resource themselves.
+	Message struct {
+		Type       string          `json:"type"`
+		QuestionId string          `json:"questionId,omitempty"`
+		AccountId  string          `json:"accountId,omitempty"`
+		Data       json.RawMessage `json:"data,omitempty"`
+	}
+
+	Presence struct {
+		Viewing   int `json:"viewing"`
+		Answering int `json:"answering"`
+	}
+
+	StreamQuery struct {
+		QuestionId string
+	}
+)
+
+func NewMessage(messageType, questionId string, data interface{}) (Message, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		Type:       messageType,
+		QuestionId: questionId,
+		Data:       raw,
+	}, nil
+}
+
+func QuestionTopic(questionId string) string {
+	return "question:" + questionId
+}
+
+func AccountTopic(accountId string) string {
+	return "account:" + accountId
+}
+
+// Topic is the topic of the clients the message is delivered to, messages for
+// an account are private to the account.
+func (m Message) Topic() string {
+	if m.AccountId != "" {
+		return AccountTopic(m.AccountId)
+	}
+
+	return QuestionTopic(m.QuestionId)
+}
+
+func NewStreamQuery(url url.Values) StreamQuery {
+	return StreamQuery{
+		QuestionId: url.Get("questionId"),
+	}
+}
+
+func ValidateStreamQuery(q StreamQuery) error {
+	return validation.Errors{
+		"questionId": validation.Validate(q.QuestionId, validation.Required, is.UUID),
+	}.Filter()
+}
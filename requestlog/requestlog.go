@@ -0,0 +1,108 @@
+package requestlog
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"golang.org/x/exp/slog"
+)
+
+const Header = "X-Request-Id"
+
+// an incoming id is only trusted when it looks like an id, so clients can not
+// write anything they like into the logs
+var validId = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+var logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	Level: slog.LevelInfo,
+}))
+
+type (
+	ctxKey struct{}
+
+	// entry is what the access log learns while the request is handled. It is
+	// shared through the context because the routes add their values to
+	// copies of the request.
+	entry struct {
+		requestId string
+		accountId string
+	}
+)
+
+func fromContext(ctx context.Context) *entry {
+	e, _ := ctx.Value(ctxKey{}).(*entry)
+
+	return e
+}
+
+// NewContext carries the request id in a context that was not created by the
+// middleware
+func NewContext(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &entry{requestId: requestId})
+}
+
+// RequestId is empty when the context was not created by the middleware
+func RequestId(ctx context.Context) string {
+	if e := fromContext(ctx); e != nil {
+		return e.requestId
+	}
+
+	return ""
+}
+
+// SetAccountId records the account of the request in its access log
+func SetAccountId(ctx context.Context, accountId string) {
+	if e := fromContext(ctx); e != nil {
+		e.accountId = accountId
+	}
+}
+
+// Middleware takes the id of the request from X-Request-Id or generates one,
+// puts it in the context and in the response, and writes one access log line
+// once the request is handled. It has to run before the routes so the route
+// pattern is known when the line is written.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestId := r.Header.Get(Header)
+		if !validId.MatchString(requestId) {
+			requestId = uuid.NewString()
+		}
+
+		e := &entry{requestId: requestId}
+
+		w.Header().Set(Header, requestId)
+
+		// keeps http.Flusher and http.Hijacker of the writer for the streams
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), ctxKey{}, e)))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		route := ""
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			route = rctx.RoutePattern()
+		}
+
+		logger.Info("request",
+			slog.String("requestId", requestId),
+			slog.String("method", r.Method),
+			slog.String("route", route),
+			slog.Int("status", status),
+			slog.Int64("latencyMs", time.Since(start).Milliseconds()),
+			slog.Int("bytes", ww.BytesWritten()),
+			slog.String("accountId", e.accountId),
+		)
+	})
+}
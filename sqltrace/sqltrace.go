@@ -0,0 +1,283 @@
+package sqltrace
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+)
+
+var logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	Level: slog.LevelWarn,
+}))
+
+var (
+	stringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numericLiteral = regexp.MustCompile(`([^\w$.])\d+(?:\.\d+)?\b`)
+	whitespace     = regexp.MustCompile(`\s+`)
+)
+
+// Sanitize removes the literals from a statement, the arguments are never in
+// it but a few statements inline their values. The statement is also put on
+// one line.
+func Sanitize(query string) string {
+	query = stringLiteral.ReplaceAllString(query, "?")
+	query = numericLiteral.ReplaceAllString(query, "${1}?")
+
+	return strings.TrimSpace(whitespace.ReplaceAllString(query, " "))
+}
+
+type (
+	// Options of the instrumentation, queries slower than SlowThreshold are
+	// logged, a zero threshold logs none.
+	Options struct {
+		Tracer        trace.Tracer
+		System        string
+		SlowThreshold time.Duration
+	}
+
+	connector struct {
+		driver.Connector
+		opt Options
+	}
+
+	conn interface {
+		driver.Conn
+		driver.QueryerContext
+		driver.ExecerContext
+		driver.ConnPrepareContext
+		driver.ConnBeginTx
+		driver.Pinger
+		driver.SessionResetter
+		driver.Validator
+	}
+
+	tracedConn struct {
+		conn
+		opt Options
+	}
+
+	tracedStmt struct {
+		driver.Stmt
+		query string
+		opt   Options
+	}
+
+	// rows has every optional interface of the rows of lib/pq, so the wrapper
+	// hides none of them from database/sql
+	rows interface {
+		driver.Rows
+		driver.RowsNextResultSet
+		driver.RowsColumnTypeScanType
+		driver.RowsColumnTypeDatabaseTypeName
+		driver.RowsColumnTypeLength
+		driver.RowsColumnTypePrecisionScale
+	}
+
+	tracedRows struct {
+		rows
+		span trace.Span
+		read int
+	}
+)
+
+// NewConnector records a span for every statement sent through the
+// connections of the connector, when the context of the statement carries a
+// span. Statements without a parent span, such as migrations, are not traced
+// but slow ones are still logged.
+func NewConnector(c driver.Connector, opt Options) driver.Connector {
+	return connector{Connector: c, opt: opt}
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	dc, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	traceable, ok := dc.(conn)
+	if !ok {
+		return dc, nil
+	}
+
+	return tracedConn{conn: traceable, opt: c.opt}, nil
+}
+
+// start returns a span that is not recording when ctx has no span
+func (o Options) start(ctx context.Context, operation, query string) (trace.Span, string) {
+	statement := Sanitize(query)
+
+	if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		return trace.SpanFromContext(ctx), statement
+	}
+
+	_, span := o.Tracer.Start(ctx, fmt.Sprintf("sql.%s", operation),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", o.System),
+			attribute.String("db.statement", statement),
+		),
+	)
+
+	return span, statement
+}
+
+// finish records the outcome of a statement, driver.ErrSkip only asks
+// database/sql to prepare the statement first.
+func (o Options) finish(ctx context.Context, span trace.Span, statement string, started time.Time, err error) {
+	elapsed := time.Since(started)
+
+	span.SetAttributes(attribute.Int64("db.duration_ms", elapsed.Milliseconds()))
+
+	if err != nil && !errors.Is(err, driver.ErrSkip) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, fmt.Sprintf("error while run statement: %v", err))
+	}
+
+	if o.SlowThreshold > 0 && elapsed >= o.SlowThreshold {
+		logger.Warn("slow query",
+			slog.String("statement", statement),
+			slog.Int64("durationMs", elapsed.Milliseconds()),
+			slog.String("traceId", trace.SpanContextFromContext(ctx).TraceID().String()),
+		)
+	}
+}
+
+func (c tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return traceQuery(ctx, c.opt, query, func() (driver.Rows, error) {
+		return c.conn.QueryContext(ctx, query, args)
+	})
+}
+
+func (c tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return traceExec(ctx, c.opt, query, func() (driver.Result, error) {
+		return c.conn.ExecContext(ctx, query, args)
+	})
+}
+
+func (c tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.conn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return tracedStmt{Stmt: stmt, query: query, opt: c.opt}, nil
+}
+
+func (s tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return traceQuery(ctx, s.opt, s.query, func() (driver.Rows, error) {
+		if stmt, ok := s.Stmt.(driver.StmtQueryContext); ok {
+			return stmt.QueryContext(ctx, args)
+		}
+
+		values, err := toValues(args)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.Stmt.Query(values) //nolint:staticcheck
+	})
+}
+
+func (s tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return traceExec(ctx, s.opt, s.query, func() (driver.Result, error) {
+		if stmt, ok := s.Stmt.(driver.StmtExecContext); ok {
+			return stmt.ExecContext(ctx, args)
+		}
+
+		values, err := toValues(args)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.Stmt.Exec(values) //nolint:staticcheck
+	})
+}
+
+func toValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("named arguments are not supported")
+		}
+
+		values[i] = arg.Value
+	}
+
+	return values, nil
+}
+
+// traceQuery keeps the span open until the rows are closed, so it counts the
+// rows that were read.
+func traceQuery(ctx context.Context, opt Options, q string, run func() (driver.Rows, error)) (driver.Rows, error) {
+	var (
+		started         = time.Now()
+		span, statement = opt.start(ctx, "query", q)
+		result, err     = run()
+	)
+
+	opt.finish(ctx, span, statement, started, err)
+
+	if err != nil {
+		span.End()
+		return nil, err
+	}
+
+	traceable, ok := result.(rows)
+	if !ok {
+		span.End()
+		return result, nil
+	}
+
+	return &tracedRows{rows: traceable, span: span}, nil
+}
+
+func traceExec(ctx context.Context, opt Options, q string, run func() (driver.Result, error)) (driver.Result, error) {
+	var (
+		started         = time.Now()
+		span, statement = opt.start(ctx, "exec", q)
+		result, err     = run()
+	)
+	defer span.End()
+
+	opt.finish(ctx, span, statement, started, err)
+
+	if err == nil {
+		if affected, err := result.RowsAffected(); err == nil {
+			span.SetAttributes(attribute.Int64("db.rows_affected", affected))
+		}
+	}
+
+	return result, err
+}
+
+func (r *tracedRows) Next(dest []driver.Value) error {
+	err := r.rows.Next(dest)
+	if err == nil {
+		r.read++
+	}
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		r.span.RecordError(err)
+	}
+
+	return err
+}
+
+func (r *tracedRows) Close() error {
+	r.span.SetAttributes(attribute.Int("db.rows_returned", r.read))
+	r.span.End()
+
+	return r.rows.Close()
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/google/uuid"
+	"github.com/rizface/quora/requestlog"
 	"golang.org/x/exp/slog"
 )
 
@@ -25,17 +26,18 @@ type Response struct {
 	TraceId   string      `json:"traceId"`
 }
 
-func Writer(w http.ResponseWriter, resp Response) {
-	w.WriteHeader(resp.Code)
-
-	requestId := w.Header().Get("X-Request-Id")
-	if requestId != "" {
-		resp.RequestId = requestId
-	} else {
+// Writer writes the response with the id of the request, the id is generated
+// when the request did not go through requestlog.Middleware.
+func Writer(w http.ResponseWriter, r *http.Request, resp Response) {
+	resp.RequestId = requestlog.RequestId(r.Context())
+	if resp.RequestId == "" {
 		resp.RequestId = uuid.NewString()
-		w.Header().Set("X-Request-Id", resp.RequestId)
 	}
 
+	// the headers are sent by WriteHeader
+	w.Header().Set(requestlog.Header, resp.RequestId)
+	w.WriteHeader(resp.Code)
+
 	if resp.Code >= 500 {
 		respB, _ := json.Marshal(resp) //nolint:errcheck
 
@@ -16,6 +16,7 @@ type services struct {
 	quora   testcontainers.Container
 	network testcontainers.Network
 	jgr     testcontainers.Container
+	redis   testcontainers.Container
 }
 
 func resolveErr(err error) {
@@ -57,7 +58,29 @@ func spawnPg(ctx context.Context, network string) testcontainers.Container {
 	return pgC
 }
 
-func spawnQuora(ctx context.Context, pgC, jgr testcontainers.Container, network string) testcontainers.Container {
+func spawnRedis(ctx context.Context, network string) testcontainers.Container {
+	req := testcontainers.ContainerRequest{
+		Image:        "redis:7",
+		ExposedPorts: []string{"6379/tcp"},
+		Networks:     []string{network},
+		WaitingFor:   wait.ForListeningPort("6379"),
+	}
+
+	redisC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	resolveErr(err)
+
+	port, err := redisC.MappedPort(ctx, "6379/tcp")
+	resolveErr(err)
+
+	os.Setenv("REDIS_ADDR", fmt.Sprintf("localhost:%s", port.Port()))
+
+	return redisC
+}
+
+func spawnQuora(ctx context.Context, pgC, jgr, redisC testcontainers.Container, network string) testcontainers.Container {
 	ip, err := pgC.ContainerIP(ctx)
 	if err != nil {
 		log.Fatalf("failed get pgC ip: %v", err)
@@ -66,24 +89,39 @@ func spawnQuora(ctx context.Context, pgC, jgr testcontainers.Container, network
 	jgrIp, err := jgr.ContainerIP(ctx)
 	resolveErr(err)
 
+	redisIp, err := redisC.ContainerIP(ctx)
+	resolveErr(err)
+
 	req := testcontainers.ContainerRequest{
 		Image:        "quora:local",
 		ExposedPorts: []string{"3000"},
 		Networks:     []string{network},
 		Env: map[string]string{
-			"PG_HOST":             ip,
-			"PG_PORT":             "5432",
-			"PG_USER":             "pgquora",
-			"PG_PASSWORD":         "pgquora",
-			"PG_DBNAME":           "pgquora",
-			"APP_PORT":            ":3000",
-			"JWT_ACCESS_SECRET":   "access secret",
-			"JWT_REFRESH_SECRET":  "refresh secret",
-			"JAEGER_EXPORTER_URL": fmt.Sprintf("http://%s/api/traces", jgrIp),
+			"PG_HOST":            ip,
+			"PG_PORT":            "5432",
+			"PG_USER":            "pgquora",
+			"PG_PASSWORD":        "pgquora",
+			"PG_DBNAME":          "pgquora",
+			"APP_PORT":           ":3000",
+			"JWT_ACCESS_SECRET":  "access secret",
+			"JWT_REFRESH_SECRET": "refresh secret",
+			"REDIS_ADDR":         fmt.Sprintf("%s:6379", redisIp),
+			// spans are sent to jaeger over otlp
+			"OTEL_TRACES_EXPORTER":        "otlp",
+			"OTEL_EXPORTER_OTLP_PROTOCOL": "http/protobuf",
+			"OTEL_EXPORTER_OTLP_ENDPOINT": fmt.Sprintf("http://%s:4318", jgrIp),
+			// retried deliveries must be due within the test
+			"WEBHOOK_DELIVERY_INTERVAL": "1s",
+			"WEBHOOK_RETRY_BASE_DELAY":  "1h",
+			// the deliveries are sent to the app itself
+			"WEBHOOK_ALLOWED_HOSTS": "localhost",
+			// digests of the previous day are scheduled within the test
+			"DIGEST_SCHEDULE_INTERVAL": "1s",
+			"JOBS_POLL_INTERVAL":       "200ms",
 		},
 		WaitingFor: wait.ForListeningPort("3000"),
 	}
-	fmt.Println("JAEGER: ", fmt.Sprintf("http://%s:14268/api/traces", jgrIp))
+	fmt.Println("JAEGER: ", fmt.Sprintf("http://%s:4318", jgrIp))
 	quora, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
 		ContainerRequest: req,
 		Started:          true,
@@ -129,6 +167,7 @@ func spawnServices(ctx context.Context) (services, func()) {
 		pg    testcontainers.Container
 		quora testcontainers.Container
 		jgr   testcontainers.Container
+		redis testcontainers.Container
 	)
 
 	networkRequest := testcontainers.GenericNetworkRequest{
@@ -146,9 +185,10 @@ func spawnServices(ctx context.Context) (services, func()) {
 
 	pg = spawnPg(ctx, networkRequest.Name)
 	jgr = spawnJaeger(ctx, networkRequest.Name)
+	redis = spawnRedis(ctx, networkRequest.Name)
 
-	if pg.IsRunning() && jgr.IsRunning() {
-		quora = spawnQuora(ctx, pg, jgr, networkRequest.Name)
+	if pg.IsRunning() && jgr.IsRunning() && redis.IsRunning() {
+		quora = spawnQuora(ctx, pg, jgr, redis, networkRequest.Name)
 	}
 
 	if !quora.IsRunning() {
@@ -160,6 +200,7 @@ func spawnServices(ctx context.Context) (services, func()) {
 		quora:   quora,
 		network: network,
 		jgr:     jgr,
+		redis:   redis,
 	}
 
 	return svc, func() {
@@ -174,6 +215,10 @@ func spawnServices(ctx context.Context) (services, func()) {
 			log.Fatalf("failed terminal jaeger container: %v", err)
 		}
 
+		if err := svc.redis.Terminate(ctx); err != nil {
+			log.Fatalf("fail terminate redis container: %v", err)
+		}
+
 		if err := svc.network.Remove(ctx); err != nil {
 			log.Fatalf("fail remove network: %v", err)
 		}
@@ -0,0 +1,191 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rizface/quora/account/value"
+)
+
+func (suite *IntegrationTestSuite) TestDigest() {
+	type scenario struct {
+		name             string
+		method           string
+		path             func() string
+		auth             bool
+		payload          map[string]interface{}
+		checkExpectation func(resp *http.Response)
+	}
+
+	subscriber, err := value.NewAuthenticated(value.AccountEntity{
+		Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79bad",
+		Username: "testmoderator",
+		Email:    "testmoderator@gmail.com",
+	})
+	if err != nil {
+		suite.Error(err)
+	}
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	// a question of yesterday in a tag the subscriber follows
+	_, err = suite.db.ExecContext(suite.ctx, `
+		INSERT INTO follows (follower_id, target_type, target_id) VALUES ('f028ac5a-e4c9-442f-bf9a-86c024a79bad', 'tag', 'golang');
+		INSERT INTO questions (id, author_id, question, tags, created_at, updated_at) VALUES (
+			'4b9ef364-0d6a-4f60-a169-39b1d076c6d1', 'f028ac5a-e4c9-442f-bf9a-86c024a79baa',
+			'how to write a digest?', '{golang}', date_trunc('day', now()) - INTERVAL '12 hours', now()
+		);
+	`)
+	suite.NoError(err)
+
+	getDeliveries := func() (int, int) {
+		var deliveries, questions int
+
+		err := suite.db.
+			QueryRowContext(suite.ctx,
+				`SELECT COUNT(*), COALESCE(SUM(questions), 0) FROM digest_deliveries WHERE account_id = $1 AND frequency = 'daily'`,
+				"f028ac5a-e4c9-442f-bf9a-86c024a79bad",
+			).
+			Scan(&deliveries, &questions)
+		suite.NoError(err)
+
+		return deliveries, questions
+	}
+
+	getToken := func() string {
+		var token string
+
+		err := suite.db.
+			QueryRowContext(suite.ctx, "SELECT unsubscribe_token FROM digest_subscriptions WHERE account_id = $1", "f028ac5a-e4c9-442f-bf9a-86c024a79bad").
+			Scan(&token)
+		suite.NoError(err)
+
+		return token
+	}
+
+	var token string
+
+	scenarios := []scenario{
+		{
+			name:   "failed subscribe - invalid frequency",
+			method: http.MethodPut,
+			path:   func() string { return "/digest" },
+			auth:   true,
+			payload: map[string]interface{}{
+				"frequency": "hourly",
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success subscribe and receive the digest of yesterday once",
+			method: http.MethodPut,
+			path:   func() string { return "/digest" },
+			auth:   true,
+			payload: map[string]interface{}{
+				"frequency": "daily",
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				suite.Eventually(func() bool {
+					deliveries, _ := getDeliveries()
+					return deliveries == 1
+				}, 10*time.Second, 200*time.Millisecond)
+
+				// the schedule keeps running every second
+				time.Sleep(2 * time.Second)
+
+				deliveries, questions := getDeliveries()
+				suite.Equal(1, deliveries)
+				suite.Equal(1, questions)
+
+				token = getToken()
+			},
+		},
+		{
+			name:   "success get subscription",
+			method: http.MethodGet,
+			path:   func() string { return "/digest" },
+			auth:   true,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success open the unsubscribe link of the email without unsubscribing",
+			method: http.MethodGet,
+			path:   func() string { return fmt.Sprintf("/digest/unsubscribe?token=%s", token) },
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.Contains(resp.Header.Get("Content-Type"), "text/html")
+
+				var subscribed bool
+
+				err := suite.db.
+					QueryRowContext(suite.ctx, "SELECT EXISTS(SELECT 1 FROM digest_subscriptions WHERE unsubscribe_token = $1)", token).
+					Scan(&subscribed)
+				suite.NoError(err)
+				suite.True(subscribed)
+			},
+		},
+		{
+			name:   "failed unsubscribe - invalid token",
+			method: http.MethodPost,
+			path:   func() string { return "/digest/unsubscribe?token=invalid" },
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success unsubscribe with the token of the email",
+			method: http.MethodPost,
+			path:   func() string { return fmt.Sprintf("/digest/unsubscribe?token=%s", token) },
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:   "failed get subscription - unsubscribed",
+			method: http.MethodGet,
+			path:   func() string { return "/digest" },
+			auth:   true,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:     fmt.Sprintf("http://%s%s", url, s.path()),
+				method:  s.method,
+				payload: s.payload,
+				headers: map[string]string{},
+			}
+
+			if s.auth {
+				r.headers["Authorization"] = fmt.Sprintf("Bearer %s", subscriber.Tokens[0].Value)
+			}
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Error(err)
+			}
+
+			defer resp.Body.Close()
+
+			if s.checkExpectation != nil {
+				s.checkExpectation(resp)
+			}
+		})
+	}
+}
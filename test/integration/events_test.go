@@ -0,0 +1,295 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/rizface/quora/account/value"
+	"github.com/rizface/quora/events"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// only the bus of this test subscribes to it, so the relay of the app never
+// claims it
+type crashTested struct {
+	Value string `json:"value"`
+}
+
+func (crashTested) Name() string { return "test.crashed" }
+
+type deadTested struct{}
+
+func (deadTested) Name() string { return "test.dead" }
+
+func (suite *IntegrationTestSuite) TestEventsOutbox() {
+	var (
+		tracer = trace.NewNoopTracerProvider().Tracer("integration")
+		repo   = events.NewRepository(suite.db, tracer)
+		calls  = map[string]int{}
+	)
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	record := func(commit bool) {
+		tx, err := suite.db.BeginTx(suite.ctx, nil)
+		suite.NoError(err)
+		defer tx.Rollback() //nolint:errcheck
+
+		suite.NoError(events.Record(suite.ctx, tx, crashTested{Value: "outbox"}))
+
+		if commit {
+			suite.NoError(tx.Commit())
+		}
+	}
+
+	getPublishedAt := func() sql.NullTime {
+		var publishedAt sql.NullTime
+
+		err := suite.db.
+			QueryRowContext(suite.ctx, "SELECT published_at FROM events WHERE name = $1", crashTested{}.Name()).
+			Scan(&publishedAt)
+		suite.NoError(err)
+
+		return publishedAt
+	}
+
+	// newRelay stands for a fresh process, every consumer counts its calls
+	newRelay := func(second func(ctx context.Context, tx *sql.Tx, e crashTested) error) *events.Relay {
+		bus := events.NewBus(repo, tracer)
+
+		events.Subscribe(bus, "test.first", func(ctx context.Context, tx *sql.Tx, e crashTested) error {
+			calls["test.first"]++
+			return nil
+		})
+		events.Subscribe(bus, "test.second", second)
+
+		return events.NewRelay(suite.db, "", repo, bus, tracer)
+	}
+
+	suite.Run("success drop the event of a rolled back change", func() {
+		record(false)
+
+		var total int
+
+		err := suite.db.
+			QueryRowContext(suite.ctx, "SELECT COUNT(*) FROM events WHERE name = $1", crashTested{}.Name()).
+			Scan(&total)
+		suite.NoError(err)
+		suite.Equal(0, total)
+	})
+
+	suite.Run("success keep the event when the relay crashes mid publish", func() {
+		record(true)
+
+		ctx, crash := context.WithCancel(suite.ctx)
+		defer crash()
+
+		relay := newRelay(func(ctx context.Context, tx *sql.Tx, e crashTested) error {
+			calls["test.second"]++
+			crash()
+
+			return ctx.Err()
+		})
+
+		_, err := relay.Flush(ctx)
+		suite.Error(err)
+
+		suite.Equal(1, calls["test.first"])
+		suite.Equal(1, calls["test.second"])
+		suite.False(getPublishedAt().Valid)
+	})
+
+	suite.Run("success publish the event again after a restart, once per consumer", func() {
+		relay := newRelay(func(ctx context.Context, tx *sql.Tx, e crashTested) error {
+			calls["test.second"]++
+			suite.Equal("outbox", e.Value)
+
+			return nil
+		})
+
+		published, err := relay.Flush(suite.ctx)
+		suite.NoError(err)
+		suite.Equal(1, published)
+
+		// the first consumer handled it before the crash
+		suite.Equal(1, calls["test.first"])
+		suite.Equal(2, calls["test.second"])
+		suite.True(getPublishedAt().Valid)
+
+		published, err = relay.Flush(suite.ctx)
+		suite.NoError(err)
+		suite.Equal(0, published)
+		suite.Equal(2, calls["test.second"])
+	})
+}
+
+func (suite *IntegrationTestSuite) TestDeadEvents() {
+	type scenario struct {
+		name             string
+		method           string
+		path             func() string
+		token            string
+		checkExpectation func(resp *http.Response)
+	}
+
+	newToken := func(account value.AccountEntity) string {
+		authenticated, err := value.NewAuthenticated(account)
+		if err != nil {
+			suite.Error(err)
+		}
+
+		return authenticated.Tokens[0].Value
+	}
+
+	var (
+		member = newToken(value.AccountEntity{
+			Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+			Username: "testlogin",
+			Email:    "testlogin@gmail.com",
+		})
+		moderator = newToken(value.AccountEntity{
+			Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79bad",
+			Username: "testmoderator",
+			Email:    "testmoderator@gmail.com",
+		})
+		tracer = trace.NewNoopTracerProvider().Tracer("integration")
+		repo   = events.NewRepository(suite.db, tracer)
+		bus    = events.NewBus(repo, tracer)
+		broken = true
+		calls  int
+		dead   string
+	)
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	os.Setenv("EVENTS_MAX_ATTEMPTS", "2")
+	defer os.Unsetenv("EVENTS_MAX_ATTEMPTS")
+
+	events.Subscribe(bus, "test.dead", func(ctx context.Context, tx *sql.Tx, e deadTested) error {
+		calls++
+
+		if broken {
+			return errors.New("always fails")
+		}
+
+		return nil
+	})
+
+	relay := events.NewRelay(suite.db, "", repo, bus, tracer)
+
+	tx, err := suite.db.BeginTx(suite.ctx, nil)
+	suite.NoError(err)
+	suite.NoError(events.Record(suite.ctx, tx, deadTested{}))
+	suite.NoError(tx.Commit())
+
+	suite.Run("success mark the event dead once the attempts ran out", func() {
+		for i := 0; i < 2; i++ {
+			_, err := relay.Flush(suite.ctx)
+			suite.NoError(err)
+
+			_, err = suite.db.ExecContext(suite.ctx, "UPDATE events SET next_attempt_at = now() WHERE name = $1", deadTested{}.Name())
+			suite.NoError(err)
+		}
+
+		_, err := relay.Flush(suite.ctx)
+		suite.NoError(err)
+		suite.Equal(2, calls)
+
+		err = suite.db.
+			QueryRowContext(suite.ctx, "SELECT id FROM events WHERE name = $1 AND dead_at IS NOT NULL", deadTested{}.Name()).
+			Scan(&dead)
+		suite.NoError(err)
+	})
+
+	scenarios := []scenario{
+		{
+			name:   "failed get dead events - not a moderator",
+			method: http.MethodGet,
+			path:   func() string { return "/admin/events" },
+			token:  member,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success get dead events",
+			method: http.MethodGet,
+			path:   func() string { return "/admin/events" },
+			token:  moderator,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var result struct {
+					Data struct {
+						Docs []events.DeadEvent `json:"docs"`
+					} `json:"data"`
+				}
+
+				suite.NoError(json.NewDecoder(resp.Body).Decode(&result))
+				suite.Len(result.Data.Docs, 1)
+				suite.Equal(dead, result.Data.Docs[0].Id)
+				suite.Equal("always fails", result.Data.Docs[0].LastError.String)
+			},
+		},
+		{
+			name:   "success retry dead event",
+			method: http.MethodPost,
+			path:   func() string { return fmt.Sprintf("/admin/events/%s/retry", dead) },
+			token:  moderator,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:   "failed retry event - not dead",
+			method: http.MethodPost,
+			path:   func() string { return fmt.Sprintf("/admin/events/%s/retry", dead) },
+			token:  moderator,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:    fmt.Sprintf("http://%s%s", url, s.path()),
+				method: s.method,
+				headers: map[string]string{
+					"Authorization": fmt.Sprintf("Bearer %s", s.token),
+				},
+			}
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Error(err)
+			}
+
+			defer resp.Body.Close()
+
+			if s.checkExpectation != nil {
+				s.checkExpectation(resp)
+			}
+		})
+	}
+
+	suite.Run("success publish the retried event", func() {
+		broken = false
+
+		published, err := relay.Flush(suite.ctx)
+		suite.NoError(err)
+		suite.Equal(1, published)
+		suite.Equal(3, calls)
+	})
+}
@@ -0,0 +1,141 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rizface/quora/account/value"
+)
+
+func (suite *IntegrationTestSuite) TestFeed() {
+	type (
+		scenario struct {
+			name             string
+			url              string
+			method           string
+			payload          map[string]interface{}
+			checkExpectation func(resp *http.Response)
+		}
+
+		response struct {
+			Data struct {
+				Docs []struct {
+					Question struct {
+						Id string `json:"id"`
+					} `json:"question"`
+					Reason string `json:"reason"`
+				} `json:"docs"`
+			} `json:"data"`
+		}
+	)
+
+	authenticated, err := value.NewAuthenticated(value.AccountEntity{
+		Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+		Username: "testlogin",
+		Email:    "testlogin@gmail.com",
+	})
+	if err != nil {
+		suite.Error(err)
+	}
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	scenarios := []scenario{
+		{
+			name:    "failed follow - invalid type",
+			url:     "follows",
+			method:  http.MethodPost,
+			payload: map[string]interface{}{"type": "planet", "targetId": "earth"},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:    "success follow an account",
+			url:     "follows",
+			method:  http.MethodPost,
+			payload: map[string]interface{}{"type": "account", "targetId": "f028ac5a-e4c9-442f-bf9a-86c024a79bac"},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:   "feed starts with the questions of the followed account",
+			url:    "feed?limit=1",
+			method: http.MethodGet,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var result response
+
+				suite.NoError(json.NewDecoder(resp.Body).Decode(&result))
+				suite.Len(result.Data.Docs, 1)
+				suite.Equal("4b9ef364-0d6a-4f60-a169-39b1d076c63b", result.Data.Docs[0].Question.Id)
+				suite.Equal("account", result.Data.Docs[0].Reason)
+			},
+		},
+		{
+			name:   "feed does not repeat seen questions",
+			url:    "feed?limit=1",
+			method: http.MethodGet,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var result response
+
+				suite.NoError(json.NewDecoder(resp.Body).Decode(&result))
+
+				for _, doc := range result.Data.Docs {
+					suite.NotEqual("4b9ef364-0d6a-4f60-a169-39b1d076c63b", doc.Question.Id)
+				}
+			},
+		},
+		{
+			name:   "success unfollow an account",
+			url:    "follows/account/f028ac5a-e4c9-442f-bf9a-86c024a79bac",
+			method: http.MethodDelete,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var unseen int
+
+				err := suite.db.QueryRowContext(suite.ctx, `
+					SELECT COUNT(*) FROM feed_items fi INNER JOIN questions q ON q.id = fi.question_id
+					WHERE fi.account_id = $1 AND q.author_id = $2
+				`, "f028ac5a-e4c9-442f-bf9a-86c024a79baa", "f028ac5a-e4c9-442f-bf9a-86c024a79bac").Scan(&unseen)
+				suite.NoError(err)
+				suite.Equal(0, unseen)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:     fmt.Sprintf("http://%s/%s", url, s.url),
+				payload: s.payload,
+				method:  s.method,
+				headers: map[string]string{
+					"Authorization": fmt.Sprintf("Bearer %s", authenticated.Tokens[0].Value),
+				},
+			}
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Error(err)
+			}
+
+			defer resp.Body.Close()
+
+			if s.checkExpectation != nil {
+				s.checkExpectation(resp)
+			}
+		})
+	}
+}
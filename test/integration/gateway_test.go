@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rizface/quora/account/value"
+)
+
+func (suite *IntegrationTestSuite) TestWebSocketGateway() {
+	type message struct {
+		Type       string                 `json:"type"`
+		QuestionId string                 `json:"questionId"`
+		Data       map[string]interface{} `json:"data"`
+	}
+
+	watcher, err := value.NewAuthenticated(value.AccountEntity{
+		Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+		Username: "testlogin",
+		Email:    "testlogin@gmail.com",
+	})
+	if err != nil {
+		suite.Error(err)
+	}
+
+	answerer, err := value.NewAuthenticated(value.AccountEntity{
+		Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79bad",
+		Username: "testmoderator",
+		Email:    "testmoderator@gmail.com",
+	})
+	if err != nil {
+		suite.Error(err)
+	}
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	url, err := suite.services.quora.Endpoint(suite.ctx, "")
+	if err != nil {
+		suite.Error(err)
+	}
+
+	// waits for the first message of the given type, skipping the others
+	waitFor := func(ws *websocket.Conn, messageType string) message {
+		ws.SetReadDeadline(time.Now().Add(10 * time.Second)) //nolint:errcheck
+
+		for {
+			var msg message
+
+			if err := ws.ReadJSON(&msg); err != nil {
+				suite.T().Fatalf("failed wait for %s message: %v", messageType, err)
+			}
+
+			if msg.Type == messageType {
+				return msg
+			}
+		}
+	}
+
+	suite.Run("failed connect - missing token", func() {
+		_, resp, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://%s/ws", url), nil)
+		suite.Error(err)
+		suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	suite.Run("success receive presence, question updates and notifications", func() {
+		follow := requester{
+			url:    fmt.Sprintf("http://%s/questions/4b9ef364-0d6a-4f60-a169-39b1d076c65e/follow", url),
+			method: http.MethodPost,
+			headers: map[string]string{
+				"Authorization": fmt.Sprintf("Bearer %s", watcher.Tokens[0].Value),
+			},
+		}
+
+		followResp, err := follow.do()
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+		followResp.Body.Close()
+
+		ws, _, err := websocket.DefaultDialer.Dial(
+			fmt.Sprintf("ws://%s/ws?token=%s", url, watcher.Tokens[0].Value), nil,
+		)
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+		defer ws.Close()
+
+		err = ws.WriteJSON(map[string]string{
+			"action":     "subscribe",
+			"questionId": "4b9ef364-0d6a-4f60-a169-39b1d076c65e",
+		})
+		suite.NoError(err)
+
+		waitFor(ws, "subscribed")
+
+		presence := waitFor(ws, "presence")
+		suite.Equal(float64(1), presence.Data["viewing"])
+
+		err = ws.WriteJSON(map[string]string{
+			"action":     "presence",
+			"questionId": "4b9ef364-0d6a-4f60-a169-39b1d076c65e",
+			"activity":   "answering",
+		})
+		suite.NoError(err)
+
+		presence = waitFor(ws, "presence")
+		suite.Equal(float64(0), presence.Data["viewing"])
+		suite.Equal(float64(1), presence.Data["answering"])
+
+		answer := requester{
+			url: fmt.Sprintf("http://%s/answers", url),
+			payload: map[string]interface{}{
+				"answer":     "answered while you were watching",
+				"questionId": "4b9ef364-0d6a-4f60-a169-39b1d076c65e",
+			},
+			method: http.MethodPost,
+			headers: map[string]string{
+				"Authorization": fmt.Sprintf("Bearer %s", answerer.Tokens[0].Value),
+			},
+		}
+
+		answerResp, err := answer.do()
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+		answerResp.Body.Close()
+
+		suite.Equal(http.StatusOK, answerResp.StatusCode)
+
+		notification := waitFor(ws, "notification")
+		suite.Equal("answer.created", notification.Data["type"])
+
+		suite.NoError(ws.WriteJSON(map[string]string{"action": "ping"}))
+		waitFor(ws, "pong")
+
+		err = ws.WriteJSON(map[string]string{
+			"action":     "subscribe",
+			"questionId": "invalid",
+		})
+		suite.NoError(err)
+
+		waitFor(ws, "error")
+	})
+}
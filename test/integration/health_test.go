@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rizface/quora/health"
+)
+
+func (suite *IntegrationTestSuite) TestHealth() {
+	type scenario struct {
+		name             string
+		path             string
+		checkExpectation func(report health.Report)
+	}
+
+	scenarios := []scenario{
+		{
+			name: "success live",
+			path: "livez",
+			checkExpectation: func(report health.Report) {
+				suite.Equal(health.StatusOk, report.Status)
+			},
+		},
+		{
+			name: "success ready without the checks",
+			path: "readyz",
+			checkExpectation: func(report health.Report) {
+				suite.Equal(health.StatusOk, report.Status)
+				suite.Empty(report.Checks)
+			},
+		},
+		{
+			name: "success ready with every check",
+			path: "readyz?verbose",
+			checkExpectation: func(report health.Report) {
+				suite.Equal(health.StatusOk, report.Status)
+
+				for _, check := range []string{"db", "migrations", "cache", "trace_exporter"} {
+					suite.Contains(report.Checks, check)
+					suite.Equal(health.StatusOk, report.Checks[check].Status, report.Checks[check].Error)
+				}
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			resp, err := requester{
+				url:    fmt.Sprintf("http://%s/%s", url, s.path),
+				method: http.MethodGet,
+			}.do()
+			if err != nil {
+				suite.T().Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			suite.Equal(http.StatusOK, resp.StatusCode)
+
+			var report health.Report
+
+			suite.NoError(json.NewDecoder(resp.Body).Decode(&report))
+
+			s.checkExpectation(report)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rizface/quora/account/value"
+)
+
+func (suite *IntegrationTestSuite) TestIdempotency() {
+	type scenario struct {
+		name             string
+		user             string
+		key              string
+		payload          map[string]interface{}
+		checkExpectation func(resp *http.Response)
+	}
+
+	var (
+		users = map[string]value.AccountEntity{
+			"user1": {
+				Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+				Username: "testlogin",
+				Email:    "testlogin@gmail.com",
+			},
+			"user2": {
+				Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79bac",
+				Username: "testdelete",
+				Email:    "testdelete@gmail.com",
+			},
+		}
+
+		usersToken = map[string]string{}
+		created    string
+	)
+
+	for k, v := range users {
+		authenticated, err := value.NewAuthenticated(v)
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+
+		usersToken[k] = authenticated.Tokens[0].Value
+	}
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	questionId := func(resp *http.Response) string {
+		var body struct {
+			Data struct {
+				Doc struct {
+					Id string `json:"id"`
+				} `json:"doc"`
+			} `json:"data"`
+		}
+
+		suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+
+		return body.Data.Doc.Id
+	}
+
+	countQuestions := func(question string) int {
+		var n int
+
+		err := suite.db.QueryRowContext(suite.ctx, "SELECT COUNT(*) FROM questions WHERE question = $1", question).Scan(&n)
+		suite.NoError(err)
+
+		return n
+	}
+
+	scenarios := []scenario{
+		{
+			name:    "success create question - first request with the key",
+			user:    "user1",
+			key:     "retry-me",
+			payload: map[string]interface{}{"question": "is this sent only once ?"},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.Empty(resp.Header.Get("Idempotent-Replayed"))
+
+				created = questionId(resp)
+				suite.NotEmpty(created)
+			},
+		},
+		{
+			name:    "success create question - retry replays the response",
+			user:    "user1",
+			key:     "retry-me",
+			payload: map[string]interface{}{"question": "is this sent only once ?"},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.Equal("true", resp.Header.Get("Idempotent-Replayed"))
+				suite.Equal(created, questionId(resp))
+				suite.Equal(1, countQuestions("is this sent only once ?"))
+			},
+		},
+		{
+			name:    "failed create question - same key with another body",
+			user:    "user1",
+			key:     "retry-me",
+			payload: map[string]interface{}{"question": "is this another question ?"},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+				suite.Equal(0, countQuestions("is this another question ?"))
+			},
+		},
+		{
+			name:    "success create question - same key of another account",
+			user:    "user2",
+			key:     "retry-me",
+			payload: map[string]interface{}{"question": "is this sent only once ?"},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.Empty(resp.Header.Get("Idempotent-Replayed"))
+				suite.NotEqual(created, questionId(resp))
+				suite.Equal(2, countQuestions("is this sent only once ?"))
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			resp, err := requester{
+				url:     fmt.Sprintf("http://%s/questions", url),
+				method:  http.MethodPost,
+				payload: s.payload,
+				headers: map[string]string{
+					"Authorization":   fmt.Sprintf("Bearer %s", usersToken[s.user]),
+					"Idempotency-Key": s.key,
+				},
+			}.do()
+			if err != nil {
+				suite.T().Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			s.checkExpectation(resp)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/rizface/quora/account/value"
+	"github.com/rizface/quora/jobs"
+	jobvalue "github.com/rizface/quora/jobs/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// the test jobs are only handled by the runner of this test, the runner of
+// the app never claims them
+type (
+	flakyJob struct {
+		Name string `json:"name"`
+	}
+
+	brokenJob struct{}
+
+	slowJob struct{}
+)
+
+func (flakyJob) Kind() string  { return "test.flaky" }
+func (brokenJob) Kind() string { return "test.broken" }
+func (slowJob) Kind() string   { return "test.slow" }
+
+func (suite *IntegrationTestSuite) TestJobs() {
+	type scenario struct {
+		name             string
+		method           string
+		path             func() string
+		token            string
+		checkExpectation func(resp *http.Response)
+	}
+
+	newToken := func(account value.AccountEntity) string {
+		authenticated, err := value.NewAuthenticated(account)
+		if err != nil {
+			suite.Error(err)
+		}
+
+		return authenticated.Tokens[0].Value
+	}
+
+	var (
+		member = newToken(value.AccountEntity{
+			Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+			Username: "testlogin",
+			Email:    "testlogin@gmail.com",
+		})
+		moderator = newToken(value.AccountEntity{
+			Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79bad",
+			Username: "testmoderator",
+			Email:    "testmoderator@gmail.com",
+		})
+		tracer     = trace.NewNoopTracerProvider().Tracer("integration")
+		flakyCalls atomic.Int32
+		slowCalls  atomic.Int32
+		failed     string
+	)
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	os.Setenv("JOBS_POLL_INTERVAL", "100ms")
+	os.Setenv("JOBS_RETRY_BASE_DELAY", "100ms")
+
+	runner := jobs.NewRunner(jobs.NewRepository(suite.db, tracer), tracer)
+
+	jobs.Register(runner, func(ctx context.Context, job flakyJob) error {
+		if flakyCalls.Add(1) == 1 {
+			return errors.New("first attempt fails")
+		}
+
+		return nil
+	})
+	jobs.Register(runner, func(ctx context.Context, job brokenJob) error {
+		return errors.New("always fails")
+	})
+	jobs.Register(runner, func(ctx context.Context, job slowJob) error {
+		time.Sleep(500 * time.Millisecond)
+		slowCalls.Add(1)
+
+		return nil
+	})
+
+	getJob := func(kind string) jobvalue.Job {
+		var job jobvalue.Job
+
+		err := suite.db.
+			QueryRowContext(suite.ctx, "SELECT id, status, attempts FROM jobs WHERE kind = $1", kind).
+			Scan(&job.Id, &job.Status, &job.Attempts)
+		suite.NoError(err)
+
+		return job
+	}
+
+	suite.Run("success retry a failed job with backoff", func() {
+		runner.Start()
+
+		suite.NoError(runner.Enqueue(suite.ctx, flakyJob{Name: "flaky"}, jobvalue.Options{}))
+		suite.NoError(runner.Enqueue(suite.ctx, brokenJob{}, jobvalue.Options{MaxAttempts: 2}))
+
+		suite.Eventually(func() bool {
+			return getJob(flakyJob{}.Kind()).Status == jobvalue.StatusSucceeded &&
+				getJob(brokenJob{}.Kind()).Status == jobvalue.StatusFailed
+		}, 10*time.Second, 100*time.Millisecond)
+
+		suite.Equal(2, getJob(flakyJob{}.Kind()).Attempts)
+		suite.Equal(2, getJob(brokenJob{}.Kind()).Attempts)
+
+		failed = getJob(brokenJob{}.Kind()).Id
+	})
+
+	suite.Run("success keep a delayed job pending", func() {
+		err := runner.Enqueue(suite.ctx, slowJob{}, jobvalue.Options{
+			Queue: jobvalue.DefaultQueue,
+			RunAt: time.Now().Add(time.Hour),
+		})
+		suite.NoError(err)
+
+		time.Sleep(300 * time.Millisecond)
+		suite.Equal(jobvalue.StatusPending, getJob(slowJob{}.Kind()).Status)
+	})
+
+	suite.Run("success drain the running jobs on stop", func() {
+		_, err := suite.db.ExecContext(suite.ctx, "UPDATE jobs SET run_at = now() WHERE kind = $1", slowJob{}.Kind())
+		suite.NoError(err)
+
+		suite.Eventually(func() bool {
+			return getJob(slowJob{}.Kind()).Status == jobvalue.StatusRunning
+		}, 5*time.Second, 50*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(suite.ctx, 5*time.Second)
+		defer cancel()
+
+		suite.NoError(runner.Stop(ctx))
+		suite.Equal(int32(1), slowCalls.Load())
+		suite.Equal(jobvalue.StatusSucceeded, getJob(slowJob{}.Kind()).Status)
+	})
+
+	scenarios := []scenario{
+		{
+			name:   "failed get failed jobs - not a moderator",
+			method: http.MethodGet,
+			path:   func() string { return "/admin/jobs" },
+			token:  member,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success get failed jobs",
+			method: http.MethodGet,
+			path:   func() string { return "/admin/jobs?status=failed" },
+			token:  moderator,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var result struct {
+					Data struct {
+						Docs []jobvalue.Job `json:"docs"`
+					} `json:"data"`
+				}
+
+				suite.NoError(json.NewDecoder(resp.Body).Decode(&result))
+				suite.Len(result.Data.Docs, 1)
+				suite.Equal(failed, result.Data.Docs[0].Id)
+				suite.Equal("always fails", result.Data.Docs[0].LastError.String)
+			},
+		},
+		{
+			name:   "success retry failed job",
+			method: http.MethodPost,
+			path:   func() string { return fmt.Sprintf("/admin/jobs/%s/retry", failed) },
+			token:  moderator,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.Equal(jobvalue.StatusPending, getJob(brokenJob{}.Kind()).Status)
+			},
+		},
+		{
+			name:   "failed retry job - not failed",
+			method: http.MethodPost,
+			path:   func() string { return fmt.Sprintf("/admin/jobs/%s/retry", failed) },
+			token:  moderator,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:    fmt.Sprintf("http://%s%s", url, s.path()),
+				method: s.method,
+				headers: map[string]string{
+					"Authorization": fmt.Sprintf("Bearer %s", s.token),
+				},
+			}
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Error(err)
+			}
+
+			defer resp.Body.Close()
+
+			if s.checkExpectation != nil {
+				s.checkExpectation(resp)
+			}
+		})
+	}
+}
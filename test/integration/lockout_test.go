@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+func (suite *IntegrationTestSuite) TestLoginLockout() {
+	type scenario struct {
+		name string
+		// seeds the failed logins of the account
+		failures         string
+		password         string
+		checkExpectation func(resp *http.Response)
+	}
+
+	accountId := "f028ac5a-e4c9-442f-bf9a-86c024a79baa"
+
+	count := func(query string, args ...interface{}) int {
+		var n int
+
+		if err := suite.db.QueryRowContext(suite.ctx, query, args...).Scan(&n); err != nil {
+			suite.T().Fatal(err)
+		}
+
+		return n
+	}
+
+	scenarios := []scenario{
+		{
+			name:     "failed login - delayed after too many failures",
+			failures: "failures = 7, last_failed_at = now()",
+			password: "testdata",
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusTooManyRequests, resp.StatusCode)
+
+				retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+				suite.NoError(err)
+				suite.Greater(retryAfter, 0)
+			},
+		},
+		{
+			name:     "failed login - locked by the failure that reaches the threshold",
+			failures: "failures = 9, last_failed_at = now() - INTERVAL '10 minutes'",
+			password: "wrongpassword",
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+				suite.Equal(1, count("SELECT COUNT(*) FROM login_failures WHERE scope = 'account' AND key = $1 AND locked_until > now()", accountId))
+				suite.Equal(1, count("SELECT COUNT(*) FROM audit_logs WHERE action = 'account.locked' AND target_id = $1", accountId))
+				suite.Equal(1, count("SELECT COUNT(*) FROM jobs WHERE kind = 'account.locked' AND queue = 'mail'"))
+			},
+		},
+		{
+			name:     "failed login - locked account with the right password",
+			failures: "failures = 10, last_failed_at = now(), locked_until = now() + INTERVAL '15 minutes'",
+			password: "testdata",
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusLocked, resp.StatusCode)
+				suite.NotEmpty(resp.Header.Get("Retry-After"))
+			},
+		},
+		{
+			name:     "success login - expired lock is lifted",
+			failures: "failures = 10, last_failed_at = now() - INTERVAL '20 minutes', locked_until = now() - INTERVAL '1 minute'",
+			password: "testdata",
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				suite.Equal(0, count("SELECT COUNT(*) FROM login_failures WHERE scope = 'account' AND key = $1", accountId))
+				suite.Equal(1, count("SELECT COUNT(*) FROM audit_logs WHERE action = 'account.unlocked' AND target_id = $1", accountId))
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			ImportSQL(suite.db, "../../testdata/account/login.sql")
+
+			_, err := suite.db.ExecContext(suite.ctx, fmt.Sprintf(`
+				INSERT INTO login_failures (scope, key) VALUES ('account', '%s');
+				UPDATE login_failures SET %s WHERE scope = 'account';
+				TRUNCATE jobs;
+			`, accountId, s.failures))
+			suite.NoError(err)
+
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			resp, err := requester{
+				url:    fmt.Sprintf("http://%s/%s", url, "accounts/login"),
+				method: http.MethodPost,
+				payload: map[string]interface{}{
+					"email":    "testlogin@gmail.com",
+					"password": s.password,
+				},
+			}.do()
+			if err != nil {
+				suite.T().Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			s.checkExpectation(resp)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package integration
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+func (suite *IntegrationTestSuite) TestMetrics() {
+	ImportSQL(suite.db, "../../testdata/account/login.sql")
+
+	url, err := suite.services.quora.Endpoint(suite.ctx, "")
+	if err != nil {
+		suite.Error(err)
+	}
+
+	requests := []requester{
+		{
+			url:    fmt.Sprintf("http://%s/questions/4b9ef364-0d6a-4f60-a169-39b1d076c64b", url),
+			method: http.MethodGet,
+		},
+		{
+			url:    fmt.Sprintf("http://%s/accounts/login", url),
+			method: http.MethodPost,
+			payload: map[string]interface{}{
+				"email":    "testlogin@gmail.com",
+				"password": "testdata",
+			},
+		},
+	}
+
+	for _, r := range requests {
+		resp, err := r.do()
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+
+	resp, err := requester{
+		url:    fmt.Sprintf("http://%s/metrics", url),
+		method: http.MethodGet,
+	}.do()
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	suite.NoError(err)
+
+	for _, metric := range []string{
+		// labeled by the pattern, not by the id in the path
+		`quora_http_requests_total{method="GET",route="/questions/{id}",status="401"}`,
+		`quora_http_request_duration_seconds_bucket{method="POST",route="/accounts/login",status="200"`,
+		`quora_logins_total{result="succeeded"}`,
+		`go_sql_open_connections{db_name="quora"}`,
+		`go_goroutines`,
+	} {
+		suite.Contains(string(body), metric)
+	}
+}
@@ -0,0 +1,234 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rizface/quora/account/value"
+)
+
+func (suite *IntegrationTestSuite) TestNotifications() {
+	type (
+		scenario struct {
+			name             string
+			method           string
+			path             string
+			token            string
+			payload          map[string]interface{}
+			checkExpectation func(resp *http.Response)
+		}
+
+		response struct {
+			Data struct {
+				Docs []struct {
+					Id   string `json:"id"`
+					Type string `json:"type"`
+				} `json:"docs"`
+				Unread int `json:"unread"`
+			} `json:"data"`
+		}
+	)
+
+	newToken := func(account value.AccountEntity) string {
+		authenticated, err := value.NewAuthenticated(account)
+		if err != nil {
+			suite.Error(err)
+		}
+
+		return authenticated.Tokens[0].Value
+	}
+
+	var (
+		asker = newToken(value.AccountEntity{
+			Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+			Username: "testlogin",
+			Email:    "testlogin@gmail.com",
+		})
+		moderator = newToken(value.AccountEntity{
+			Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79bad",
+			Username: "testmoderator",
+			Email:    "testmoderator@gmail.com",
+		})
+		voter = newToken(value.AccountEntity{
+			Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79bac",
+			Username: "testdelete",
+			Email:    "testdelete@gmail.com",
+		})
+	)
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	countNotifications := func(recipientId, notificationType string) int {
+		var total int
+
+		err := suite.db.
+			QueryRowContext(suite.ctx,
+				`SELECT COUNT(*) FROM notifications WHERE recipient_id = $1 AND "type" = $2`,
+				recipientId, notificationType,
+			).
+			Scan(&total)
+		suite.NoError(err)
+
+		return total
+	}
+
+	scenarios := []scenario{
+		{
+			name:   "success follow question",
+			method: http.MethodPost,
+			path:   "/questions/4b9ef364-0d6a-4f60-a169-39b1d076c65e/follow",
+			token:  moderator,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success notify followers and mentioned accounts of a new answer",
+			method: http.MethodPost,
+			path:   "/answers",
+			token:  asker,
+			payload: map[string]interface{}{
+				"answer":     "@TestModerator what do you think?",
+				"questionId": "4b9ef364-0d6a-4f60-a169-39b1d076c65e",
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.waitForEvents()
+				suite.Equal(1, countNotifications("f028ac5a-e4c9-442f-bf9a-86c024a79bad", "answer.created"))
+				suite.Equal(1, countNotifications("f028ac5a-e4c9-442f-bf9a-86c024a79bad", "mention"))
+			},
+		},
+		{
+			name:   "success get notifications with unread count",
+			method: http.MethodGet,
+			path:   "/notifications?limit=1",
+			token:  moderator,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var result response
+
+				suite.NoError(json.NewDecoder(resp.Body).Decode(&result))
+				suite.Len(result.Data.Docs, 1)
+				suite.Equal(2, result.Data.Unread)
+			},
+		},
+		{
+			name:   "success notify the answerer of a vote",
+			method: http.MethodPatch,
+			path:   "/answers/4b9ef364-0d6a-4f60-a169-39b1d076c65d/vote",
+			token:  moderator,
+			payload: map[string]interface{}{
+				"type": "upvote",
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.waitForEvents()
+				suite.Equal(1, countNotifications("f028ac5a-e4c9-442f-bf9a-86c024a79baa", "answer.voted"))
+			},
+		},
+		{
+			name:   "failed update preference - unknown type",
+			method: http.MethodPut,
+			path:   "/notifications/preferences",
+			token:  asker,
+			payload: map[string]interface{}{
+				"type":    "unknown",
+				"enabled": false,
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success turn off vote notifications",
+			method: http.MethodPut,
+			path:   "/notifications/preferences",
+			token:  asker,
+			payload: map[string]interface{}{
+				"type":    "answer.voted",
+				"enabled": false,
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success skip notifications that are turned off",
+			method: http.MethodPatch,
+			path:   "/answers/4b9ef364-0d6a-4f60-a169-39b1d076c65d/vote",
+			token:  voter,
+			payload: map[string]interface{}{
+				"type": "upvote",
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.waitForEvents()
+				suite.Equal(1, countNotifications("f028ac5a-e4c9-442f-bf9a-86c024a79baa", "answer.voted"))
+			},
+		},
+		{
+			name:   "failed mark notification as read - not found",
+			method: http.MethodPatch,
+			path:   "/notifications/4b9ef364-0d6a-4f60-a169-39b1d076c62a/read",
+			token:  moderator,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success mark all notifications as read",
+			method: http.MethodPatch,
+			path:   "/notifications/read",
+			token:  moderator,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success get unread notifications after mark all as read",
+			method: http.MethodGet,
+			path:   "/notifications?unread=true",
+			token:  moderator,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var result response
+
+				suite.NoError(json.NewDecoder(resp.Body).Decode(&result))
+				suite.Len(result.Data.Docs, 0)
+				suite.Equal(0, result.Data.Unread)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:     fmt.Sprintf("http://%s%s", url, s.path),
+				method:  s.method,
+				payload: s.payload,
+				headers: map[string]string{
+					"Authorization": fmt.Sprintf("Bearer %s", s.token),
+				},
+			}
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Error(err)
+			}
+
+			defer resp.Body.Close()
+
+			if s.checkExpectation != nil {
+				s.checkExpectation(resp)
+			}
+		})
+	}
+}
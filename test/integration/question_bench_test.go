@@ -0,0 +1,189 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/lib/pq"
+	"github.com/rizface/quora/cache"
+	"github.com/rizface/quora/provider"
+	"github.com/rizface/quora/question"
+	"github.com/rizface/quora/question/value"
+	"github.com/testcontainers/testcontainers-go"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queries counts the statements sent through the "postgres-counted" driver
+var (
+	queries      int64
+	registerOnce sync.Once
+)
+
+type (
+	countedDriver struct {
+		driver.Driver
+	}
+
+	conn interface {
+		driver.Conn
+		driver.QueryerContext
+		driver.ExecerContext
+		driver.ConnBeginTx
+	}
+
+	countedConn struct {
+		conn
+	}
+)
+
+func (d countedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return countedConn{c.(conn)}, nil
+}
+
+func (c countedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	atomic.AddInt64(&queries, 1)
+
+	return c.conn.QueryContext(ctx, query, args)
+}
+
+func (c countedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	atomic.AddInt64(&queries, 1)
+
+	return c.conn.ExecContext(ctx, query, args)
+}
+
+func openCountedDB(b *testing.B) *sql.DB {
+	registerOnce.Do(func() {
+		sql.Register("postgres-counted", countedDriver{&pq.Driver{}})
+	})
+
+	db, err := sql.Open("postgres-counted", provider.PostgresDSN())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	instance, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance("file://../../db/migrations", "pgquora", instance)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		b.Fatal(err)
+	}
+
+	return db
+}
+
+// BenchmarkQuestionAuthors compares looking the authors of a page of 100
+// questions up one by one, as the listing used to, with the batched load.
+//
+//	go test ./test/integration -run '^$' -bench BenchmarkQuestionAuthors
+func BenchmarkQuestionAuthors(b *testing.B) {
+	ctx := context.Background()
+
+	network, err := testcontainers.GenericNetwork(ctx, testcontainers.GenericNetworkRequest{
+		NetworkRequest: testcontainers.NetworkRequest{
+			Name:       "benchmark",
+			Attachable: true,
+		},
+	})
+	resolveErr(err)
+
+	pg := spawnPg(ctx, "benchmark")
+
+	b.Cleanup(func() {
+		resolveErr(pg.Terminate(ctx))
+		resolveErr(network.Remove(ctx))
+	})
+
+	db := openCountedDB(b)
+	defer db.Close()
+
+	ImportSQL(db, "../../testdata/question/benchmark_questions.sql")
+
+	var (
+		tracer = trace.NewNoopTracerProvider().Tracer("benchmark")
+		query  = value.QuestionQuery{Limit: 100, Sort: value.SortNew, Window: value.WindowAll}
+		// a new cache on every operation, so the authors are never cached
+		newRepo = func() *question.Repository {
+			return question.NewRepository(db, cache.New(cache.NewMemoryStore(), tracer), tracer)
+		}
+	)
+
+	page, err := newRepo().GetList(ctx, query)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if len(page) != 100 {
+		b.Fatalf("expected a page of 100 questions, got %d", len(page))
+	}
+
+	authorIds := make([]string, 0, len(page))
+	for _, q := range page {
+		authorIds = append(authorIds, q.AuthorId)
+	}
+
+	run := func(b *testing.B, op func() error) {
+		atomic.StoreInt64(&queries, 0)
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			if err := op(); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		b.StopTimer()
+		b.ReportMetric(float64(atomic.LoadInt64(&queries))/float64(b.N), "queries/op")
+	}
+
+	b.Run("authors one by one", func(b *testing.B) {
+		run(b, func() error {
+			for _, authorId := range authorIds {
+				var author value.Author
+
+				err := db.
+					QueryRowContext(ctx, `SELECT id, username FROM accounts WHERE id = $1`, authorId).
+					Scan(&author.Id, &author.Username)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+
+	b.Run("authors batched", func(b *testing.B) {
+		run(b, func() error {
+			_, err := newRepo().GetAuthors(ctx, authorIds)
+			return err
+		})
+	})
+
+	b.Run("list with authors batched", func(b *testing.B) {
+		run(b, func() error {
+			_, err := newRepo().GetList(ctx, query)
+			return err
+		})
+	})
+}
@@ -73,6 +73,58 @@ func (suite *IntegrationTestSuite) TestCreateNewQuestion() {
 				assert.Nil(t, err)
 			},
 		},
+		{
+			name: "failed create one question - banned word in space",
+			payload: map[string]interface{}{
+				"question": "which casino is the best ?",
+				"spaceId":  "a53152d7-2d24-42e1-a55f-649e87349ffa",
+			},
+			checkExpectation: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "question with banned word must be rejected")
+
+				type response struct {
+					Data map[string]map[string]string `json:"data"`
+				}
+
+				var result response
+
+				err := json.NewDecoder(resp.Body).Decode(&result)
+				assert.Nil(t, err)
+				assert.Equal(t, `contains banned word "casino"`, result.Data["doc"]["question"])
+			},
+		},
+		{
+			name: "failed create one question - too many links in space",
+			payload: map[string]interface{}{
+				"question": "which one is better https://a.com or https://b.com ?",
+				"spaceId":  "a53152d7-2d24-42e1-a55f-649e87349ffa",
+			},
+			checkExpectation: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "question with too many links must be rejected")
+			},
+		},
+		{
+			name: "success create one question - shouting is sent to moderation",
+			payload: map[string]interface{}{
+				"question": "WHY IS MY CODE NOT WORKING ????",
+				"spaceId":  nil,
+			},
+			checkExpectation: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode, "shouting question must be created")
+
+				var count int
+
+				err := suite.db.
+					QueryRowContext(suite.ctx,
+						"SELECT COUNT(*) FROM moderation_queue WHERE author_id = $1 AND content_type = 'question'",
+						"f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+					).
+					Scan(&count)
+
+				assert.Nil(t, err)
+				assert.Equal(t, 1, count)
+			},
+		},
 	}
 
 	t := suite.T()
@@ -118,6 +170,7 @@ func (suite *IntegrationTestSuite) TestVoteAnswer() {
 	type answer struct {
 		upvote   int
 		downvote int
+		score    float64
 	}
 
 	authenticated, err := value.NewAuthenticated(value.AccountEntity{
@@ -142,16 +195,17 @@ func (suite *IntegrationTestSuite) TestVoteAnswer() {
 				var (
 					question = answer{}
 					query    = `
-						select upvote from answers where id = $1
+						select upvote, score from answers where id = $1
 					`
 				)
 
-				err := suite.db.QueryRowContext(suite.ctx, query, "4b9ef364-0d6a-4f60-a169-39b1d076c65d").Scan(&question.upvote)
+				err := suite.db.QueryRowContext(suite.ctx, query, "4b9ef364-0d6a-4f60-a169-39b1d076c65d").Scan(&question.upvote, &question.score)
 				if err != nil {
 					t.Error(err)
 				}
 
 				assert.Equal(t, 1, question.upvote)
+				assert.InDelta(t, 0.2065, question.score, 0.0001, "score must be the wilson lower bound of 1 upvote")
 			},
 		},
 		{
@@ -682,3 +736,795 @@ func prinResponse(t *testing.T, w *http.Response) {
 
 	fmt.Println(body)
 }
+
+func (suite *IntegrationTestSuite) TestMarkQuestionAsDuplicate() {
+	type scenario struct {
+		name             string
+		questionId       string
+		token            string
+		payload          map[string]interface{}
+		checkExpectation func(resp *http.Response)
+	}
+
+	var (
+		users = map[string]value.AccountEntity{
+			"member": {
+				Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+				Username: "testlogin",
+				Email:    "testlogin@gmail.com",
+			},
+			"moderator": {
+				Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79bad",
+				Username: "testmoderator",
+				Email:    "testmoderator@gmail.com",
+			},
+		}
+
+		usersToken = map[string]string{}
+	)
+
+	for k, v := range users {
+		authenticated, err := value.NewAuthenticated(v)
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+
+		usersToken[k] = authenticated.Tokens[0].Value
+	}
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	scenarios := []scenario{
+		{
+			name:       "failed mark as duplicate - not a moderator",
+			questionId: "4b9ef364-0d6a-4f60-a169-39b1d076c65e",
+			token:      usersToken["member"],
+			payload: map[string]interface{}{
+				"canonicalId": "4b9ef364-0d6a-4f60-a169-39b1d076c65d",
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name:       "failed mark as duplicate - duplicate of itself",
+			questionId: "4b9ef364-0d6a-4f60-a169-39b1d076c65e",
+			token:      usersToken["moderator"],
+			payload: map[string]interface{}{
+				"canonicalId": "4b9ef364-0d6a-4f60-a169-39b1d076c65e",
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusConflict, resp.StatusCode)
+			},
+		},
+		{
+			name:       "success mark as duplicate and move the answers",
+			questionId: "4b9ef364-0d6a-4f60-a169-39b1d076c65e",
+			token:      usersToken["moderator"],
+			payload: map[string]interface{}{
+				"canonicalId": "4b9ef364-0d6a-4f60-a169-39b1d076c65d",
+				"moveAnswers": true,
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var duplicateOf sql.NullString
+
+				err := suite.db.
+					QueryRow(`SELECT duplicate_of FROM questions WHERE id = $1`, "4b9ef364-0d6a-4f60-a169-39b1d076c65e").
+					Scan(&duplicateOf)
+				suite.NoError(err)
+				suite.Equal("4b9ef364-0d6a-4f60-a169-39b1d076c65d", duplicateOf.String)
+
+				var answers int
+
+				err = suite.db.
+					QueryRow(`SELECT COUNT(*) FROM answers WHERE question_id = $1`, "4b9ef364-0d6a-4f60-a169-39b1d076c65d").
+					Scan(&answers)
+				suite.NoError(err)
+				suite.Equal(4, answers)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:     fmt.Sprintf("http://%s/%s/%s/duplicate", url, "questions", s.questionId),
+				payload: s.payload,
+				method:  http.MethodPost,
+				headers: map[string]string{
+					"Authorization": "Bearer " + s.token,
+				},
+			}
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Error(err)
+			}
+
+			defer resp.Body.Close()
+
+			if s.checkExpectation != nil {
+				s.checkExpectation(resp)
+			}
+		})
+	}
+}
+
+func (suite *IntegrationTestSuite) TestQuestionState() {
+	type scenario struct {
+		name             string
+		url              string
+		method           string
+		token            string
+		payload          map[string]interface{}
+		checkExpectation func(resp *http.Response)
+	}
+
+	var (
+		users = map[string]value.AccountEntity{
+			"member": {
+				Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+				Username: "testlogin",
+				Email:    "testlogin@gmail.com",
+			},
+			"moderator": {
+				Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79bad",
+				Username: "testmoderator",
+				Email:    "testmoderator@gmail.com",
+			},
+		}
+
+		usersToken = map[string]string{}
+	)
+
+	for k, v := range users {
+		authenticated, err := value.NewAuthenticated(v)
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+
+		usersToken[k] = authenticated.Tokens[0].Value
+	}
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	scenarios := []scenario{
+		{
+			name:    "failed close question - not a moderator",
+			url:     "questions/4b9ef364-0d6a-4f60-a169-39b1d076c65f/close",
+			method:  http.MethodPost,
+			token:   usersToken["member"],
+			payload: map[string]interface{}{"reason": "off topic"},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name:    "success close question",
+			url:     "questions/4b9ef364-0d6a-4f60-a169-39b1d076c65f/close",
+			method:  http.MethodPost,
+			token:   usersToken["moderator"],
+			payload: map[string]interface{}{"reason": "off topic"},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var state string
+
+				err := suite.db.
+					QueryRow(`SELECT state FROM questions WHERE id = $1`, "4b9ef364-0d6a-4f60-a169-39b1d076c65f").
+					Scan(&state)
+				suite.NoError(err)
+				suite.Equal("closed", state)
+			},
+		},
+		{
+			name:   "failed answer closed question",
+			url:    "answers",
+			method: http.MethodPost,
+			token:  usersToken["member"],
+			payload: map[string]interface{}{
+				"answer":     "late answer",
+				"questionId": "4b9ef364-0d6a-4f60-a169-39b1d076c65f",
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusConflict, resp.StatusCode)
+			},
+		},
+		{
+			name:   "reopen vote from member does not reopen the question yet",
+			url:    "questions/4b9ef364-0d6a-4f60-a169-39b1d076c65f/reopen",
+			method: http.MethodPost,
+			token:  usersToken["member"],
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var state string
+
+				err := suite.db.
+					QueryRow(`SELECT state FROM questions WHERE id = $1`, "4b9ef364-0d6a-4f60-a169-39b1d076c65f").
+					Scan(&state)
+				suite.NoError(err)
+				suite.Equal("closed", state)
+			},
+		},
+		{
+			name:   "success reopen question by moderator",
+			url:    "questions/4b9ef364-0d6a-4f60-a169-39b1d076c65f/reopen",
+			method: http.MethodPost,
+			token:  usersToken["moderator"],
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var state string
+
+				err := suite.db.
+					QueryRow(`SELECT state FROM questions WHERE id = $1`, "4b9ef364-0d6a-4f60-a169-39b1d076c65f").
+					Scan(&state)
+				suite.NoError(err)
+				suite.Equal("open", state)
+			},
+		},
+		{
+			name:    "success lock question",
+			url:     "questions/4b9ef364-0d6a-4f60-a169-39b1d076c65e/lock",
+			method:  http.MethodPost,
+			token:   usersToken["moderator"],
+			payload: map[string]interface{}{"reason": "edit war"},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:    "failed vote answer of locked question",
+			url:     "answers/4b9ef364-0d6a-4f60-a169-39b1d076c65d/vote",
+			method:  http.MethodPatch,
+			token:   usersToken["member"],
+			payload: map[string]interface{}{"type": "upvote"},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusLocked, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:     fmt.Sprintf("http://%s/%s", url, s.url),
+				payload: s.payload,
+				method:  s.method,
+				headers: map[string]string{
+					"Authorization": "Bearer " + s.token,
+				},
+			}
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Error(err)
+			}
+
+			defer resp.Body.Close()
+
+			if s.checkExpectation != nil {
+				s.checkExpectation(resp)
+			}
+		})
+	}
+}
+
+func (suite *IntegrationTestSuite) TestGetRelatedQuestions() {
+	type response struct {
+		Data struct {
+			Docs []map[string]interface{} `json:"docs"`
+		} `json:"data"`
+	}
+
+	authenticated, err := value.NewAuthenticated(value.AccountEntity{
+		Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+		Username: "testlogin",
+		Email:    "testlogin@gmail.com",
+	})
+	if err != nil {
+		suite.Error(err)
+	}
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	url, err := suite.services.quora.Endpoint(suite.ctx, "")
+	suite.NoError(err)
+
+	r := requester{
+		url:    fmt.Sprintf("http://%s/questions/%s/related?limit=3", url, "4b9ef364-0d6a-4f60-a169-39b1d076c65d"),
+		method: http.MethodGet,
+		headers: map[string]string{
+			"Authorization": fmt.Sprintf("Bearer %s", authenticated.Tokens[0].Value),
+		},
+	}
+
+	resp, err := r.do()
+	suite.NoError(err)
+	defer resp.Body.Close()
+
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	var result response
+
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	suite.Len(result.Data.Docs, 3)
+
+	var cached int
+
+	err = suite.db.
+		QueryRow(`SELECT COUNT(*) FROM related_questions WHERE question_id = $1`, "4b9ef364-0d6a-4f60-a169-39b1d076c65d").
+		Scan(&cached)
+	suite.NoError(err)
+	suite.Greater(cached, 0, "related questions must be cached")
+
+	err = suite.db.
+		QueryRow(`SELECT COUNT(*) FROM related_computations WHERE question_id = $1`, "4b9ef364-0d6a-4f60-a169-39b1d076c65d").
+		Scan(&cached)
+	suite.NoError(err)
+	suite.Equal(1, cached, "the computation must be recorded")
+}
+
+func (suite *IntegrationTestSuite) TestGetQuestions() {
+	type scenario struct {
+		name             string
+		query            string
+		checkExpectation func(resp *http.Response)
+	}
+
+	authenticated, err := value.NewAuthenticated(value.AccountEntity{
+		Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+		Username: "testlogin",
+		Email:    "testlogin@gmail.com",
+	})
+	if err != nil {
+		suite.Error(err)
+	}
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	scenarios := []scenario{
+		{
+			name:  "success get hot questions",
+			query: "sort=hot",
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:  "success get top questions of all time",
+			query: "sort=top&window=all",
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var result struct {
+					Data struct {
+						Docs []map[string]interface{} `json:"docs"`
+					} `json:"data"`
+				}
+
+				suite.NoError(json.NewDecoder(resp.Body).Decode(&result))
+				suite.Len(result.Data.Docs, 1)
+			},
+		},
+		{
+			name:  "failed get questions - invalid sort",
+			query: "sort=random",
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:  "failed get questions - invalid window",
+			query: "sort=top&window=year",
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:  "failed get questions - invalid space id",
+			query: "space_ids=foo",
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:    fmt.Sprintf("http://%s/questions?%s", url, s.query),
+				method: http.MethodGet,
+				headers: map[string]string{
+					"Authorization": fmt.Sprintf("Bearer %s", authenticated.Tokens[0].Value),
+				},
+			}
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Error(err)
+			}
+
+			defer resp.Body.Close()
+
+			if s.checkExpectation != nil {
+				s.checkExpectation(resp)
+			}
+		})
+	}
+}
+
+func (suite *IntegrationTestSuite) TestGetAnswersOfQuestion() {
+	type scenario struct {
+		name             string
+		query            string
+		checkExpectation func(resp *http.Response)
+	}
+
+	authenticated, err := value.NewAuthenticated(value.AccountEntity{
+		Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+		Username: "testlogin",
+		Email:    "testlogin@gmail.com",
+	})
+	if err != nil {
+		suite.Error(err)
+	}
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	scenarios := []scenario{
+		{
+			name:  "success get answers sorted by score",
+			query: "questionId=4b9ef364-0d6a-4f60-a169-39b1d076c65e",
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var result struct {
+					Data struct {
+						Docs []map[string]interface{} `json:"docs"`
+					} `json:"data"`
+				}
+
+				suite.NoError(json.NewDecoder(resp.Body).Decode(&result))
+				suite.Len(result.Data.Docs, 4)
+			},
+		},
+		{
+			name:  "failed get answers - question not found",
+			query: "questionId=4b9ef364-0d6a-4f60-a169-39b1d076c62a",
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:  "failed get answers - invalid sort",
+			query: "questionId=4b9ef364-0d6a-4f60-a169-39b1d076c65e&sort=random",
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:    fmt.Sprintf("http://%s/answers?%s", url, s.query),
+				method: http.MethodGet,
+				headers: map[string]string{
+					"Authorization": fmt.Sprintf("Bearer %s", authenticated.Tokens[0].Value),
+				},
+			}
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Error(err)
+			}
+
+			defer resp.Body.Close()
+
+			if s.checkExpectation != nil {
+				s.checkExpectation(resp)
+			}
+		})
+	}
+}
+
+func (suite *IntegrationTestSuite) TestFollowQuestion() {
+	type scenario struct {
+		name             string
+		method           string
+		path             string
+		token            string
+		payload          map[string]interface{}
+		checkExpectation func(resp *http.Response)
+	}
+
+	asker, err := value.NewAuthenticated(value.AccountEntity{
+		Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+		Username: "testlogin",
+		Email:    "testlogin@gmail.com",
+	})
+	if err != nil {
+		suite.Error(err)
+	}
+
+	follower, err := value.NewAuthenticated(value.AccountEntity{
+		Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79bad",
+		Username: "testmoderator",
+		Email:    "testmoderator@gmail.com",
+	})
+	if err != nil {
+		suite.Error(err)
+	}
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	countNotifications := func(recipientId string) int {
+		var total int
+
+		err := suite.db.
+			QueryRowContext(suite.ctx, "SELECT COUNT(*) FROM notifications WHERE recipient_id = $1", recipientId).
+			Scan(&total)
+		suite.NoError(err)
+
+		return total
+	}
+
+	scenarios := []scenario{
+		{
+			name:   "failed follow question - question not found",
+			method: http.MethodPost,
+			path:   "/questions/4b9ef364-0d6a-4f60-a169-39b1d076c62a/follow",
+			token:  follower.Tokens[0].Value,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success follow question",
+			method: http.MethodPost,
+			path:   "/questions/4b9ef364-0d6a-4f60-a169-39b1d076c65e/follow",
+			token:  follower.Tokens[0].Value,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success get followed questions",
+			method: http.MethodGet,
+			path:   "/me/following/questions",
+			token:  follower.Tokens[0].Value,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var result struct {
+					Data struct {
+						Docs []map[string]interface{} `json:"docs"`
+					} `json:"data"`
+				}
+
+				suite.NoError(json.NewDecoder(resp.Body).Decode(&result))
+				suite.Len(result.Data.Docs, 1)
+				suite.Equal("4b9ef364-0d6a-4f60-a169-39b1d076c65e", result.Data.Docs[0]["id"])
+			},
+		},
+		{
+			name:   "success notify followers of a new answer",
+			method: http.MethodPost,
+			path:   "/answers",
+			token:  asker.Tokens[0].Value,
+			payload: map[string]interface{}{
+				"answer":     "you can follow this question",
+				"questionId": "4b9ef364-0d6a-4f60-a169-39b1d076c65e",
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.waitForEvents()
+				suite.Equal(1, countNotifications("f028ac5a-e4c9-442f-bf9a-86c024a79bad"))
+				// the answerer is not notified of its own answer
+				suite.Equal(0, countNotifications("f028ac5a-e4c9-442f-bf9a-86c024a79baa"))
+			},
+		},
+		{
+			name:   "success unfollow question",
+			method: http.MethodDelete,
+			path:   "/questions/4b9ef364-0d6a-4f60-a169-39b1d076c65e/follow",
+			token:  follower.Tokens[0].Value,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:   "failed unfollow question - not following",
+			method: http.MethodDelete,
+			path:   "/questions/4b9ef364-0d6a-4f60-a169-39b1d076c65e/follow",
+			token:  follower.Tokens[0].Value,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:     fmt.Sprintf("http://%s%s", url, s.path),
+				method:  s.method,
+				payload: s.payload,
+				headers: map[string]string{
+					"Authorization": fmt.Sprintf("Bearer %s", s.token),
+				},
+			}
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Error(err)
+			}
+
+			defer resp.Body.Close()
+
+			if s.checkExpectation != nil {
+				s.checkExpectation(resp)
+			}
+		})
+	}
+}
+
+func (suite *IntegrationTestSuite) TestQuestionCache() {
+	type scenario struct {
+		name             string
+		method           string
+		payload          map[string]interface{}
+		preTest          func()
+		checkExpectation func(resp *http.Response)
+	}
+
+	authenticated, err := value.NewAuthenticated(value.AccountEntity{
+		Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+		Username: "testlogin",
+		Email:    "testlogin@gmail.com",
+	})
+	if err != nil {
+		suite.Error(err)
+	}
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	var (
+		idQuestion = "4b9ef364-0d6a-4f60-a169-39b1d076c64b"
+
+		getQuestion = func(resp *http.Response) string {
+			var result struct {
+				Data struct {
+					Doc struct {
+						Question string `json:"question"`
+					} `json:"doc"`
+				} `json:"data"`
+			}
+
+			suite.NoError(json.NewDecoder(resp.Body).Decode(&result))
+
+			return result.Data.Doc.Question
+		}
+	)
+
+	scenarios := []scenario{
+		{
+			name:   "success get question - read from the database",
+			method: http.MethodGet,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.Equal("before update", getQuestion(resp))
+
+				exists, err := suite.redis.Exists(suite.ctx, fmt.Sprintf("questions:%s", idQuestion)).Result()
+				suite.NoError(err)
+				suite.Equal(int64(1), exists)
+			},
+		},
+		{
+			name:   "success get question - read from the cache",
+			method: http.MethodGet,
+			preTest: func() {
+				_, err := suite.db.ExecContext(suite.ctx, "UPDATE questions SET question = 'changed behind the cache' WHERE id = $1", idQuestion)
+				suite.NoError(err)
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.Equal("before update", getQuestion(resp))
+			},
+		},
+		{
+			name:   "success update question - invalidates the cache",
+			method: http.MethodPut,
+			payload: map[string]interface{}{
+				"question": "updated",
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success get question - read again from the database",
+			method: http.MethodGet,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.Equal("updated", getQuestion(resp))
+			},
+		},
+		{
+			name:   "success delete question - invalidates the cache",
+			method: http.MethodDelete,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:   "failed get question - deleted",
+			method: http.MethodGet,
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			if s.preTest != nil {
+				s.preTest()
+			}
+
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:     fmt.Sprintf("http://%s/questions/%s", url, idQuestion),
+				method:  s.method,
+				payload: s.payload,
+				headers: map[string]string{
+					"Authorization": fmt.Sprintf("Bearer %s", authenticated.Tokens[0].Value),
+				},
+			}
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Error(err)
+			}
+
+			defer resp.Body.Close()
+
+			if s.checkExpectation != nil {
+				s.checkExpectation(resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/rizface/quora/account/value"
+)
+
+func (suite *IntegrationTestSuite) TestRateLimit() {
+	type scenario struct {
+		name    string
+		path    string
+		token   string
+		payload func(i int) map[string]interface{}
+		// requests sent before the one that is checked
+		before           int
+		checkBefore      func(i int, resp *http.Response)
+		checkExpectation func(resp *http.Response)
+	}
+
+	var (
+		users = map[string]value.AccountEntity{
+			"user1": {
+				Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+				Username: "testlogin",
+				Email:    "testlogin@gmail.com",
+			},
+			"user2": {
+				Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79bac",
+				Username: "testdelete",
+				Email:    "testdelete@gmail.com",
+			},
+		}
+
+		usersToken = map[string]string{}
+	)
+
+	for k, v := range users {
+		authenticated, err := value.NewAuthenticated(v)
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+
+		usersToken[k] = authenticated.Tokens[0].Value
+	}
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	scenarios := []scenario{
+		{
+			name: "failed login - too many attempts from the same ip",
+			path: "accounts/login",
+			payload: func(i int) map[string]interface{} {
+				return map[string]interface{}{
+					"email":    "testlogin@gmail.com",
+					"password": "wrongpassword",
+				}
+			},
+			before: 5,
+			checkBefore: func(i int, resp *http.Response) {
+				suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+				suite.Equal("5", resp.Header.Get("X-RateLimit-Limit"))
+				suite.Equal(strconv.Itoa(4-i), resp.Header.Get("X-RateLimit-Remaining"))
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusTooManyRequests, resp.StatusCode)
+				suite.Equal("0", resp.Header.Get("X-RateLimit-Remaining"))
+				suite.NotEmpty(resp.Header.Get("X-RateLimit-Reset"))
+
+				retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+				suite.NoError(err)
+				suite.Greater(retryAfter, 0)
+			},
+		},
+		{
+			name:  "failed create question - too many questions from the same account",
+			path:  "questions",
+			token: usersToken["user1"],
+			payload: func(i int) map[string]interface{} {
+				return map[string]interface{}{
+					"question": fmt.Sprintf("is question number %d spam ?", i),
+				}
+			},
+			before: 10,
+			checkBefore: func(i int, resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusTooManyRequests, resp.StatusCode)
+				suite.NotEmpty(resp.Header.Get("Retry-After"))
+			},
+		},
+		{
+			name:  "success create question - another account from the same ip",
+			path:  "questions",
+			token: usersToken["user2"],
+			payload: func(i int) map[string]interface{} {
+				return map[string]interface{}{
+					"question": fmt.Sprintf("is question number %d spam ?", i),
+				}
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.Equal("9", resp.Header.Get("X-RateLimit-Remaining"))
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:     fmt.Sprintf("http://%s/%s", url, s.path),
+				method:  http.MethodPost,
+				headers: map[string]string{},
+			}
+
+			if s.token != "" {
+				r.headers["Authorization"] = fmt.Sprintf("Bearer %s", s.token)
+			}
+
+			for i := 0; i < s.before; i++ {
+				r.payload = s.payload(i)
+
+				resp, err := r.do()
+				if err != nil {
+					suite.T().Fatal(err)
+				}
+
+				resp.Body.Close()
+
+				if s.checkBefore != nil {
+					s.checkBefore(i, resp)
+				}
+			}
+
+			r.payload = s.payload(s.before)
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Error(err)
+			}
+
+			defer resp.Body.Close()
+
+			if s.checkExpectation != nil {
+				s.checkExpectation(resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package integration
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rizface/quora/account/value"
+)
+
+func (suite *IntegrationTestSuite) TestStream() {
+	authenticated, err := value.NewAuthenticated(value.AccountEntity{
+		Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+		Username: "testlogin",
+		Email:    "testlogin@gmail.com",
+	})
+	if err != nil {
+		suite.Error(err)
+	}
+
+	token := authenticated.Tokens[0].Value
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	url, err := suite.services.quora.Endpoint(suite.ctx, "")
+	if err != nil {
+		suite.Error(err)
+	}
+
+	suite.Run("failed open stream - missing token", func() {
+		r := requester{
+			url:    fmt.Sprintf("http://%s/stream?questionId=4b9ef364-0d6a-4f60-a169-39b1d076c65e", url),
+			method: http.MethodGet,
+		}
+
+		resp, err := r.do()
+		if err != nil {
+			suite.T().Error(err)
+		}
+		defer resp.Body.Close()
+
+		suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	suite.Run("failed open stream - invalid question id", func() {
+		r := requester{
+			url:    fmt.Sprintf("http://%s/stream?questionId=invalid&token=%s", url, token),
+			method: http.MethodGet,
+		}
+
+		resp, err := r.do()
+		if err != nil {
+			suite.T().Error(err)
+		}
+		defer resp.Body.Close()
+
+		suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	suite.Run("success receive the answer created on the watched question", func() {
+		ctx, cancel := context.WithTimeout(suite.ctx, 10*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+			fmt.Sprintf("http://%s/stream?questionId=4b9ef364-0d6a-4f60-a169-39b1d076c65e&token=%s", url, token), nil,
+		)
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		suite.Equal(http.StatusOK, resp.StatusCode)
+		suite.Equal("text/event-stream", resp.Header.Get("Content-Type"))
+
+		received := make(chan string, 1)
+
+		go func() {
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				if strings.HasPrefix(scanner.Text(), "event: ") {
+					received <- strings.TrimPrefix(scanner.Text(), "event: ")
+
+					return
+				}
+			}
+		}()
+
+		answer := requester{
+			url: fmt.Sprintf("http://%s/answers", url),
+			payload: map[string]interface{}{
+				"answer":     "pushed to every watcher",
+				"questionId": "4b9ef364-0d6a-4f60-a169-39b1d076c65e",
+			},
+			method: http.MethodPost,
+			headers: map[string]string{
+				"Authorization": fmt.Sprintf("Bearer %s", token),
+			},
+		}
+
+		answerResp, err := answer.do()
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+		defer answerResp.Body.Close()
+
+		suite.Equal(http.StatusOK, answerResp.StatusCode)
+
+		select {
+		case event := <-received:
+			suite.Equal("answer-created", event)
+		case <-ctx.Done():
+			suite.Fail("answer-created event was not received")
+		}
+	})
+}
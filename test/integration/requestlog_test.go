@@ -0,0 +1,78 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+func (suite *IntegrationTestSuite) TestRequestId() {
+	type scenario struct {
+		name             string
+		requestId        string
+		checkExpectation func(header string)
+	}
+
+	scenarios := []scenario{
+		{
+			name:      "success keep the incoming request id",
+			requestId: "mobile-7f3a:1",
+			checkExpectation: func(header string) {
+				suite.Equal("mobile-7f3a:1", header)
+			},
+		},
+		{
+			name: "success generate a request id",
+			checkExpectation: func(header string) {
+				suite.NotEmpty(header)
+			},
+		},
+		{
+			name:      "success replace an invalid request id",
+			requestId: "not a request id\"",
+			checkExpectation: func(header string) {
+				suite.NotEmpty(header)
+				suite.NotEqual("not a request id\"", header)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:     fmt.Sprintf("http://%s/questions", url),
+				method:  http.MethodGet,
+				headers: map[string]string{},
+			}
+
+			if s.requestId != "" {
+				r.headers["X-Request-Id"] = s.requestId
+			}
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			// unauthenticated, the id is still sent in the header and the body
+			suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+			var body struct {
+				RequestId string `json:"requestId"`
+			}
+
+			suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+
+			header := resp.Header.Get("X-Request-Id")
+			suite.Equal(header, body.RequestId)
+
+			s.checkExpectation(header)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package integration
+
+import (
+	"github.com/rizface/quora/provider"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func (suite *IntegrationTestSuite) TestSQLTracing() {
+	ImportSQL(suite.db, "../../testdata/account/login.sql")
+
+	var (
+		recorder = tracetest.NewSpanRecorder()
+		tracer   = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("integration")
+	)
+
+	db, err := provider.ProvideSQL(tracer)
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+	defer db.Close()
+
+	// without a parent span the statements are not traced
+	_, err = db.ExecContext(suite.ctx, "SELECT 1")
+	suite.NoError(err)
+
+	ctx, span := tracer.Start(suite.ctx, "parent")
+
+	_, err = db.ExecContext(ctx, "UPDATE accounts SET username = username WHERE email = 'testlogin@gmail.com'")
+	suite.NoError(err)
+
+	rows, err := db.QueryContext(ctx, "SELECT id FROM accounts WHERE email = $1", "testlogin@gmail.com")
+	suite.NoError(err)
+
+	suite.True(rows.Next())
+	suite.False(rows.Next())
+	suite.NoError(rows.Close())
+
+	_, err = db.ExecContext(ctx, "SELECT * FROM missing_table")
+	suite.Error(err)
+
+	span.End()
+
+	attributes := func(s sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+		m := map[attribute.Key]attribute.Value{}
+		for _, kv := range s.Attributes() {
+			m[kv.Key] = kv.Value
+		}
+
+		return m
+	}
+
+	ended := recorder.Ended()
+	suite.Len(ended, 4)
+
+	exec, query, failed := ended[0], ended[1], ended[2]
+
+	suite.Equal("sql.exec", exec.Name())
+	suite.Equal(span.SpanContext().SpanID(), exec.Parent().SpanID())
+	suite.Equal("UPDATE accounts SET username = username WHERE email = ?", attributes(exec)["db.statement"].AsString())
+	suite.Equal(int64(1), attributes(exec)["db.rows_affected"].AsInt64())
+
+	suite.Equal("sql.query", query.Name())
+	suite.Equal("SELECT id FROM accounts WHERE email = $1", attributes(query)["db.statement"].AsString())
+	suite.Equal(int64(1), attributes(query)["db.rows_returned"].AsInt64())
+
+	suite.Equal("sql.exec", failed.Name())
+	suite.Len(failed.Events(), 1)
+}
@@ -6,15 +6,19 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/rizface/quora/provider"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type IntegrationTestSuite struct {
 	ctx context.Context
 	suite.Suite
 	db       *sql.DB
+	redis    *redis.Client
 	services services
 	cleaner  func()
 }
@@ -26,18 +30,41 @@ func (suite *IntegrationTestSuite) SetupSuite() {
 	suite.ctx = context.Background()
 	suite.services, suite.cleaner = spawnServices(suite.ctx)
 
-	db, err := provider.ProvideSQL()
+	db, err := provider.ProvideSQL(trace.NewNoopTracerProvider().Tracer("integration"))
 	if err != nil {
 		log.Fatalf("failed when start test suite: %v", err)
 	}
 
 	suite.db = db
+	suite.redis = redis.NewClient(&redis.Options{Addr: os.Getenv("REDIS_ADDR")})
+}
+
+// SetupTest empties the cache of the app, the tests import their data
+// straight into the database.
+func (suite *IntegrationTestSuite) SetupTest() {
+	if err := suite.redis.FlushDB(suite.ctx).Err(); err != nil {
+		log.Fatalf("failed flush the cache: %v", err)
+	}
 }
 
 func (suite *IntegrationTestSuite) TearDownSuite() {
 	// suite.cleaner()
 }
 
+// waitForEvents waits until the relay published every event recorded so far,
+// the subscribers run in the background of the request that emitted them.
+func (suite *IntegrationTestSuite) waitForEvents() {
+	suite.Eventually(func() bool {
+		var pending int
+
+		err := suite.db.
+			QueryRowContext(suite.ctx, "SELECT COUNT(*) FROM events WHERE published_at IS NULL").
+			Scan(&pending)
+
+		return err == nil && pending == 0
+	}, 10*time.Second, 100*time.Millisecond)
+}
+
 func TestIntegrationTest(t *testing.T) {
 	suite.Run(t, new(IntegrationTestSuite))
 }
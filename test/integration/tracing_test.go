@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+func (suite *IntegrationTestSuite) TestTracePropagation() {
+	type scenario struct {
+		name             string
+		traceparent      string
+		checkExpectation func(traceId string)
+	}
+
+	scenarios := []scenario{
+		{
+			name:        "success continue the trace of the caller",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			checkExpectation: func(traceId string) {
+				suite.Equal("4bf92f3577b34da6a3ce929d0e0e4736", traceId)
+			},
+		},
+		{
+			name: "success start a new trace without a caller",
+			checkExpectation: func(traceId string) {
+				suite.NotEmpty(traceId)
+				suite.NotEqual("4bf92f3577b34da6a3ce929d0e0e4736", traceId)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:    fmt.Sprintf("http://%s/accounts/login", url),
+				method: http.MethodPost,
+				payload: map[string]interface{}{
+					"email":    "notfound@gmail.com",
+					"password": "testdata",
+				},
+				headers: map[string]string{},
+			}
+
+			if s.traceparent != "" {
+				r.headers["traceparent"] = s.traceparent
+			}
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			suite.Equal(http.StatusNotFound, resp.StatusCode)
+
+			var body struct {
+				TraceId string `json:"traceId"`
+			}
+
+			suite.NoError(json.NewDecoder(resp.Body).Decode(&body))
+
+			s.checkExpectation(body.TraceId)
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rizface/quora/account/value"
+)
+
+func (suite *IntegrationTestSuite) TestWebhooks() {
+	type (
+		scenario struct {
+			name             string
+			method           string
+			path             func() string
+			payload          map[string]interface{}
+			checkExpectation func(resp *http.Response)
+		}
+
+		delivery struct {
+			Id             string `json:"id"`
+			EventType      string `json:"eventType"`
+			Status         string `json:"status"`
+			Attempts       int    `json:"attempts"`
+			LastStatusCode *int   `json:"lastStatusCode"`
+		}
+	)
+
+	authenticated, err := value.NewAuthenticated(value.AccountEntity{
+		Id:       "f028ac5a-e4c9-442f-bf9a-86c024a79baa",
+		Username: "testlogin",
+		Email:    "testlogin@gmail.com",
+	})
+	if err != nil {
+		suite.Error(err)
+	}
+
+	ImportSQL(suite.db, "../../testdata/question/integration_test_questions.sql")
+
+	var subscriptionId, deliveryId string
+
+	getDelivery := func() delivery {
+		var d delivery
+
+		err := suite.db.
+			QueryRowContext(suite.ctx,
+				`SELECT id, event_type, status, attempts, last_status_code FROM webhook_deliveries WHERE subscription_id = $1`,
+				subscriptionId,
+			).
+			Scan(&d.Id, &d.EventType, &d.Status, &d.Attempts, &d.LastStatusCode)
+		suite.NoError(err)
+
+		return d
+	}
+
+	scenarios := []scenario{
+		{
+			name:   "failed subscribe - invalid url and event type",
+			method: http.MethodPost,
+			path:   func() string { return "/webhooks" },
+			payload: map[string]interface{}{
+				"url":        "ftp://example.com",
+				"eventTypes": []string{"question.viewed"},
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "failed subscribe - loopback address",
+			method: http.MethodPost,
+			path:   func() string { return "/webhooks" },
+			payload: map[string]interface{}{
+				"url":        "http://127.0.0.1:3000/webhook-sink",
+				"eventTypes": []string{"question.created"},
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "failed subscribe - link-local address",
+			method: http.MethodPost,
+			path:   func() string { return "/webhooks" },
+			payload: map[string]interface{}{
+				"url":        "http://169.254.169.254/latest/meta-data",
+				"eventTypes": []string{"question.created"},
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "failed subscribe - private address",
+			method: http.MethodPost,
+			path:   func() string { return "/webhooks" },
+			payload: map[string]interface{}{
+				"url":        "http://10.0.0.5/webhook-sink",
+				"eventTypes": []string{"question.created"},
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "failed subscribe - not the owner of the space",
+			method: http.MethodPost,
+			path:   func() string { return "/webhooks" },
+			payload: map[string]interface{}{
+				"url":     "http://localhost:3000/webhook-sink",
+				"spaceId": "a53152d7-2d24-42e1-a55f-649e87349ffb",
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success subscribe",
+			method: http.MethodPost,
+			path:   func() string { return "/webhooks" },
+			payload: map[string]interface{}{
+				// answered with 404 by the app itself, so the delivery fails
+				"url":        "http://localhost:3000/webhook-sink",
+				"eventTypes": []string{"question.created"},
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var result struct {
+					Data struct {
+						Doc struct {
+							Id     string `json:"id"`
+							Secret string `json:"secret"`
+						} `json:"doc"`
+					} `json:"data"`
+				}
+
+				suite.NoError(json.NewDecoder(resp.Body).Decode(&result))
+				suite.NotEmpty(result.Data.Doc.Secret)
+
+				subscriptionId = result.Data.Doc.Id
+			},
+		},
+		{
+			name:   "success enqueue and attempt a delivery of a subscribed event",
+			method: http.MethodPost,
+			path:   func() string { return "/questions" },
+			payload: map[string]interface{}{
+				"question": "who receives my webhooks?",
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.waitForEvents()
+
+				suite.Eventually(func() bool {
+					return getDelivery().Attempts == 1
+				}, 10*time.Second, 500*time.Millisecond)
+
+				d := getDelivery()
+				suite.Equal("question.created", d.EventType)
+				suite.Equal("pending", d.Status)
+				suite.Equal(http.StatusNotFound, *d.LastStatusCode)
+
+				deliveryId = d.Id
+			},
+		},
+		{
+			name:   "success get delivery log",
+			method: http.MethodGet,
+			path:   func() string { return fmt.Sprintf("/webhooks/%s/deliveries", subscriptionId) },
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var result struct {
+					Data struct {
+						Docs []delivery `json:"docs"`
+					} `json:"data"`
+				}
+
+				suite.NoError(json.NewDecoder(resp.Body).Decode(&result))
+				suite.Len(result.Data.Docs, 1)
+				suite.Equal(deliveryId, result.Data.Docs[0].Id)
+			},
+		},
+		{
+			name:   "success redeliver",
+			method: http.MethodPost,
+			path: func() string {
+				return fmt.Sprintf("/webhooks/%s/deliveries/%s/redeliver", subscriptionId, deliveryId)
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+
+				var attempts int
+
+				suite.Eventually(func() bool {
+					err := suite.db.
+						QueryRowContext(suite.ctx, `SELECT COUNT(*) FROM webhook_delivery_attempts WHERE delivery_id = $1`, deliveryId).
+						Scan(&attempts)
+
+					return err == nil && attempts == 2
+				}, 10*time.Second, 500*time.Millisecond)
+			},
+		},
+		{
+			name:   "failed redeliver - delivery not found",
+			method: http.MethodPost,
+			path: func() string {
+				return fmt.Sprintf("/webhooks/%s/deliveries/4b9ef364-0d6a-4f60-a169-39b1d076c62a/redeliver", subscriptionId)
+			},
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:   "success unsubscribe",
+			method: http.MethodDelete,
+			path:   func() string { return fmt.Sprintf("/webhooks/%s", subscriptionId) },
+			checkExpectation: func(resp *http.Response) {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		suite.Run(s.name, func() {
+			url, err := suite.services.quora.Endpoint(suite.ctx, "")
+			if err != nil {
+				suite.Error(err)
+			}
+
+			r := requester{
+				url:     fmt.Sprintf("http://%s%s", url, s.path()),
+				method:  s.method,
+				payload: s.payload,
+				headers: map[string]string{
+					"Authorization": fmt.Sprintf("Bearer %s", authenticated.Tokens[0].Value),
+				},
+			}
+
+			resp, err := r.do()
+			if err != nil {
+				suite.T().Error(err)
+			}
+
+			defer resp.Body.Close()
+
+			if s.checkExpectation != nil {
+				s.checkExpectation(resp)
+			}
+		})
+	}
+}
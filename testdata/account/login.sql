@@ -1,4 +1,5 @@
 TRUNCATE accounts CASCADE;
+TRUNCATE login_failures;
 
 -- password: testdata
 INSERT INTO accounts(id, email, username, password) VALUES (
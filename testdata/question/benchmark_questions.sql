@@ -0,0 +1,14 @@
+-- a page of 100 questions, each by another author, so every author is looked up
+TRUNCATE accounts CASCADE;
+
+INSERT INTO accounts(id, email, username, password)
+SELECT md5('author' || i)::uuid, 'author' || i || '@gmail.com', 'author' || i, 'benchmark'
+FROM generate_series(1, 100) i;
+
+INSERT INTO questions(id, author_id, question)
+SELECT md5('question' || i)::uuid, md5('author' || i)::uuid, 'benchmark question ' || i
+FROM generate_series(1, 100) i;
+
+INSERT INTO answers(id, question_id, answerer_id, answer)
+SELECT md5('answer' || i)::uuid, md5('question' || i)::uuid, md5('author' || (101 - i))::uuid, 'benchmark answer ' || i
+FROM generate_series(1, 100) i;
@@ -1,10 +1,14 @@
 -- password: testdata
 TRUNCATE accounts CASCADE;
+TRUNCATE events, processed_events, jobs, login_failures;
 
 INSERT INTO accounts(id, email, username, password) VALUES 
 ('f028ac5a-e4c9-442f-bf9a-86c024a79baa', 'testlogin@gmail.com', 'testlogin', '$2a$10$NXt0Uyr7XIOEM4TQZMVd7uWVkuTcG8pqTsBFZHAGg86.jjp32VtjW'),
 ('f028ac5a-e4c9-442f-bf9a-86c024a79bac', 'testdelete@gmail.com', 'testdelete', '$2a$10$NXt0Uyr7XIOEM4TQZMVd7uWVkuTcG8pqTsBFZHAGg86.jjp32VtjW');
 
+INSERT INTO accounts(id, email, username, password, role) VALUES
+('f028ac5a-e4c9-442f-bf9a-86c024a79bad', 'testmoderator@gmail.com', 'testmoderator', '$2a$10$NXt0Uyr7XIOEM4TQZMVd7uWVkuTcG8pqTsBFZHAGg86.jjp32VtjW', 'moderator');
+
 INSERT INTO spaces(id, owner_id, name) VALUES
 ('a53152d7-2d24-42e1-a55f-649e87349ffa', 'f028ac5a-e4c9-442f-bf9a-86c024a79baa', 'Ruang Programmer');
 
@@ -28,4 +32,6 @@ INSERT INTO answers(id, question_id, answerer_id, upvote, downvote, answer) VALU
 
 INSERT INTO votes(voter_id, answer_id, "type") VALUES 
 ('f028ac5a-e4c9-442f-bf9a-86c024a79baa', '4b9ef364-0d6a-4f60-a169-39b1d076c65e', 'upvote'),
-('f028ac5a-e4c9-442f-bf9a-86c024a79baa', '4b9ef364-0d6a-4f60-a169-39b1d076c65b', 'downvote');
\ No newline at end of file
+('f028ac5a-e4c9-442f-bf9a-86c024a79baa', '4b9ef364-0d6a-4f60-a169-39b1d076c65b', 'downvote');
+INSERT INTO space_content_filters(space_id, banned_words, max_links) VALUES
+('a53152d7-2d24-42e1-a55f-649e87349ffa', '{casino}', 1);
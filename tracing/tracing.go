@@ -0,0 +1,19 @@
+package tracing
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Middleware continues the trace of the caller. The trace context of the
+// traceparent header is put in the context of the request, so the spans the
+// handlers start are children of the span of the caller instead of new roots.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
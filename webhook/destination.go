@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rizface/quora/webhook/value"
+)
+
+var ErrPrivateDestination = errors.New("must not resolve to a loopback, private or link-local address")
+
+// Destinations keeps the webhooks out of the network of the app. The host of a
+// subscription is resolved when it is created, and every address is checked
+// again when it is dialed so a host that resolves to another address later is
+// refused as well.
+type Destinations struct {
+	resolver *net.Resolver
+	dialer   *net.Dialer
+	// hosts of WEBHOOK_ALLOWED_HOSTS, comma separated, are not checked
+	allowed map[string]bool
+}
+
+func NewDestinations() *Destinations {
+	allowed := map[string]bool{}
+
+	for _, host := range strings.Split(os.Getenv("WEBHOOK_ALLOWED_HOSTS"), ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			allowed[strings.ToLower(host)] = true
+		}
+	}
+
+	return &Destinations{
+		resolver: net.DefaultResolver,
+		dialer:   &net.Dialer{Timeout: 5 * time.Second},
+		allowed:  allowed,
+	}
+}
+
+// Check resolves the host of rawUrl and fails when one of its addresses is not
+// public.
+func (d *Destinations) Check(ctx context.Context, rawUrl string) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return err
+	}
+
+	host := u.Hostname()
+	if d.allowed[strings.ToLower(host)] {
+		return nil
+	}
+
+	addrs, err := d.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed resolve %s", host)
+	}
+
+	for _, addr := range addrs {
+		if !value.IsPublicIP(addr.IP) {
+			return ErrPrivateDestination
+		}
+	}
+
+	return nil
+}
+
+// DialContext is the dialer of the transport of the worker, the address it
+// connects to is checked after the host was resolved.
+func (d *Destinations) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.allowed[strings.ToLower(host)] {
+		return d.dialer.DialContext(ctx, network, address)
+	}
+
+	dialer := *d.dialer
+	dialer.Control = func(network, address string, c syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+
+		if ip := net.ParseIP(host); ip == nil || !value.IsPublicIP(ip) {
+			return fmt.Errorf("dial %s: %w", address, ErrPrivateDestination)
+		}
+
+		return nil
+	}
+
+	return dialer.DialContext(ctx, network, address)
+}
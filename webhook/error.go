@@ -0,0 +1,11 @@
+package webhook
+
+import "errors"
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrNotSpaceOwner        = errors.New("not the owner of the space")
+	// the lease of the delivery expired and another sender claimed it
+	ErrClaimLost = errors.New("webhook delivery claimed by another sender")
+)
@@ -0,0 +1,323 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rizface/quora/webhook/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Repository struct {
+	db     *sql.DB
+	tracer trace.Tracer
+}
+
+func NewRepository(db *sql.DB, tracer trace.Tracer) *Repository {
+	return &Repository{
+		db:     db,
+		tracer: tracer,
+	}
+}
+
+func (r *Repository) IsSpaceOwner(ctx context.Context, spaceId, accountId string) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "webhook.Repository.IsSpaceOwner")
+	defer span.End()
+
+	var (
+		isOwner bool
+		query   = `
+			SELECT EXISTS(SELECT 1 FROM spaces WHERE id = $1 AND owner_id = $2)
+		`
+	)
+
+	err := r.db.QueryRowContext(ctx, query, spaceId, accountId).Scan(&isOwner)
+
+	return isOwner, err
+}
+
+func (r *Repository) CreateSubscription(ctx context.Context, s value.Subscription) error {
+	ctx, span := r.tracer.Start(ctx, "webhook.Repository.CreateSubscription")
+	defer span.End()
+
+	command := `
+		INSERT INTO webhook_subscriptions (id, owner_id, space_id, url, secret, event_types, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, command,
+		s.Id, s.OwnerId, s.SpaceId, s.Url, s.Secret, pq.Array(s.EventTypes), s.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *Repository) GetSubscriptions(ctx context.Context, ownerId string) ([]value.Subscription, error) {
+	ctx, span := r.tracer.Start(ctx, "webhook.Repository.GetSubscriptions")
+	defer span.End()
+
+	var (
+		subscriptions = []value.Subscription{}
+		query         = `
+			SELECT id, owner_id, space_id, url, event_types, created_at FROM webhook_subscriptions
+			WHERE owner_id = $1 ORDER BY created_at DESC
+		`
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, ownerId)
+	if err != nil {
+		return subscriptions, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s := value.Subscription{}
+
+		err := rows.Scan(&s.Id, &s.OwnerId, &s.SpaceId, &s.Url, pq.Array(&s.EventTypes), &s.CreatedAt)
+		if err != nil {
+			return []value.Subscription{}, err
+		}
+
+		subscriptions = append(subscriptions, s)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *Repository) GetSubscription(ctx context.Context, id, ownerId string) (value.Subscription, error) {
+	ctx, span := r.tracer.Start(ctx, "webhook.Repository.GetSubscription")
+	defer span.End()
+
+	var (
+		s     = value.Subscription{}
+		query = `
+			SELECT id, owner_id, space_id, url, event_types, created_at FROM webhook_subscriptions
+			WHERE id = $1 AND owner_id = $2
+		`
+	)
+
+	err := r.db.
+		QueryRowContext(ctx, query, id, ownerId).
+		Scan(&s.Id, &s.OwnerId, &s.SpaceId, &s.Url, pq.Array(&s.EventTypes), &s.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return value.Subscription{}, ErrSubscriptionNotFound
+	}
+
+	if err != nil {
+		return value.Subscription{}, err
+	}
+
+	return s, nil
+}
+
+func (r *Repository) DeleteSubscription(ctx context.Context, s value.Subscription) error {
+	ctx, span := r.tracer.Start(ctx, "webhook.Repository.DeleteSubscription")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, s.Id)
+
+	return err
+}
+
+// Enqueue creates a pending delivery of the payload for every subscription
+// interested in the event: the subscriptions of the question author without a
+// space and the subscriptions of the space of the question.
+func (r *Repository) Enqueue(ctx context.Context, tx *sql.Tx, eventType, authorId, spaceId string, payload []byte) error {
+	ctx, span := r.tracer.Start(ctx, "webhook.Repository.Enqueue")
+	defer span.End()
+
+	command := `
+		INSERT INTO webhook_deliveries (id, subscription_id, event_type, payload)
+		SELECT gen_random_uuid(), s.id, $1, $2 FROM webhook_subscriptions s
+		WHERE (cardinality(s.event_types) = 0 OR $1 = ANY(s.event_types))
+		AND (
+			(s.space_id IS NULL AND s.owner_id = $3)
+			OR s.space_id = NULLIF($4, '')::uuid
+		)
+	`
+
+	_, err := tx.ExecContext(ctx, command, eventType, payload, authorId, spaceId)
+
+	return err
+}
+
+// Claim picks the pending deliveries that are due and pushes their next
+// attempt by the lease, so other instances skip them while they are sent. A
+// delivery whose sender died is picked up again once the lease expires, with a
+// new claim token so the late sender cannot save its attempt.
+func (r *Repository) Claim(ctx context.Context, batch int, lease time.Duration) ([]value.Delivery, error) {
+	ctx, span := r.tracer.Start(ctx, "webhook.Repository.Claim")
+	defer span.End()
+
+	var (
+		deliveries = []value.Delivery{}
+		query      = `
+			WITH due AS (
+				SELECT id FROM webhook_deliveries
+				WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+				ORDER BY next_attempt_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			UPDATE webhook_deliveries d SET
+				next_attempt_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 millisecond',
+				claim_token = gen_random_uuid()
+			FROM due, webhook_subscriptions s
+			WHERE d.id = due.id AND s.id = d.subscription_id
+			RETURNING d.id, d.subscription_id, d.event_type, d.payload, d.attempts, s.url, s.secret, d.claim_token
+		`
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, batch, lease.Milliseconds())
+	if err != nil {
+		return deliveries, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d := value.Delivery{}
+
+		err := rows.Scan(&d.Id, &d.SubscriptionId, &d.EventType, &d.Payload, &d.Attempts, &d.Url, &d.Secret, &d.ClaimToken)
+		if err != nil {
+			return []value.Delivery{}, err
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// SaveAttempt logs the attempt and moves the delivery to its next state:
+// delivered on success, dead once the attempts are exhausted and pending with
+// the given retry time otherwise. It returns ErrClaimLost and saves nothing when
+// the delivery was claimed again since the attempt started.
+func (r *Repository) SaveAttempt(ctx context.Context, a value.Attempt, status string, nextAttemptAt time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "webhook.Repository.SaveAttempt")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var (
+		statusCode = sql.NullInt64{Int64: int64(a.StatusCode), Valid: a.StatusCode != 0}
+		attemptErr = sql.NullString{String: a.Error, Valid: a.Error != ""}
+		command    = `
+			INSERT INTO webhook_delivery_attempts (delivery_id, status_code, error, duration_ms) VALUES ($1, $2, $3, $4)
+		`
+	)
+
+	if _, err := tx.ExecContext(ctx, command, a.DeliveryId, statusCode, attemptErr, a.Duration.Milliseconds()); err != nil {
+		return err
+	}
+
+	command = `
+		UPDATE webhook_deliveries SET
+			status = $2,
+			attempts = attempts + 1,
+			next_attempt_at = $3,
+			last_status_code = $4,
+			last_error = $5,
+			delivered_at = CASE WHEN $2 = 'delivered' THEN CURRENT_TIMESTAMP ELSE NULL END,
+			claim_token = NULL
+		WHERE id = $1 AND claim_token = $6
+	`
+
+	result, err := tx.ExecContext(ctx, command, a.DeliveryId, status, nextAttemptAt, statusCode, attemptErr, a.ClaimToken)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrClaimLost
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetDeliveries(ctx context.Context, s value.Subscription, q value.DeliveryQuery) ([]value.Delivery, error) {
+	ctx, span := r.tracer.Start(ctx, "webhook.Repository.GetDeliveries")
+	defer span.End()
+
+	var (
+		deliveries = []value.Delivery{}
+		query      = `
+			SELECT id, subscription_id, event_type, payload, status, attempts, next_attempt_at,
+			last_status_code, last_error, delivered_at, created_at
+			FROM webhook_deliveries
+			WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+			ORDER BY created_at DESC, id
+			LIMIT $3 OFFSET $4
+		`
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, s.Id, q.Status, q.Limit, q.Skip)
+	if err != nil {
+		return deliveries, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d := value.Delivery{}
+
+		err := rows.Scan(
+			&d.Id,
+			&d.SubscriptionId,
+			&d.EventType,
+			&d.Payload,
+			&d.Status,
+			&d.Attempts,
+			&d.NextAttemptAt,
+			&d.LastStatusCode,
+			&d.LastError,
+			&d.DeliveredAt,
+			&d.CreatedAt,
+		)
+		if err != nil {
+			return []value.Delivery{}, err
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// Redeliver schedules the delivery to be sent right away with a new round of
+// retries, the attempts already made stay in the log.
+func (r *Repository) Redeliver(ctx context.Context, s value.Subscription, deliveryId string) error {
+	ctx, span := r.tracer.Start(ctx, "webhook.Repository.Redeliver")
+	defer span.End()
+
+	command := `
+		UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP, claim_token = NULL
+		WHERE id = $1 AND subscription_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, command, deliveryId, s.Id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrDeliveryNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"context"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/rizface/quora/identifier"
+	"github.com/rizface/quora/webhook/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	Service struct {
+		tracer       trace.Tracer
+		repo         *Repository
+		destinations *Destinations
+	}
+
+	Input struct {
+		Identity            identifier.Claim
+		SubscriptionId      string
+		DeliveryId          string
+		SubscriptionPayload value.SubscriptionPayload
+		DeliveryQuery       value.DeliveryQuery
+	}
+)
+
+func NewService(repo *Repository, destinations *Destinations, tracer trace.Tracer) *Service {
+	return &Service{
+		repo:         repo,
+		destinations: destinations,
+		tracer:       tracer,
+	}
+}
+
+// Subscribe returns the subscription with its secret, the secret is not
+// returned again afterwards.
+func (s *Service) Subscribe(ctx context.Context, input Input) (value.Subscription, error) {
+	ctx, span := s.tracer.Start(ctx, "webhook.Service.Subscribe")
+	defer span.End()
+
+	subscription, err := value.NewSubscription(input.SubscriptionPayload, input.Identity.AccountId)
+	if err != nil {
+		return value.Subscription{}, err
+	}
+
+	if err := value.ValidateSubscription(subscription); err != nil {
+		return value.Subscription{}, err
+	}
+
+	if err := s.destinations.Check(ctx, subscription.Url); err != nil {
+		return value.Subscription{}, validation.Errors{"url": err}
+	}
+
+	if subscription.SpaceId.Valid {
+		isOwner, err := s.repo.IsSpaceOwner(ctx, subscription.SpaceId.String, subscription.OwnerId)
+		if err != nil {
+			return value.Subscription{}, err
+		}
+
+		if !isOwner {
+			return value.Subscription{}, ErrNotSpaceOwner
+		}
+	}
+
+	if err := s.repo.CreateSubscription(ctx, subscription); err != nil {
+		return value.Subscription{}, err
+	}
+
+	return subscription, nil
+}
+
+func (s *Service) GetSubscriptions(ctx context.Context, input Input) ([]value.Subscription, error) {
+	ctx, span := s.tracer.Start(ctx, "webhook.Service.GetSubscriptions")
+	defer span.End()
+
+	return s.repo.GetSubscriptions(ctx, input.Identity.AccountId)
+}
+
+func (s *Service) getSubscription(ctx context.Context, input Input) (value.Subscription, error) {
+	if err := value.ValidateId(input.SubscriptionId); err != nil {
+		return value.Subscription{}, ErrSubscriptionNotFound
+	}
+
+	return s.repo.GetSubscription(ctx, input.SubscriptionId, input.Identity.AccountId)
+}
+
+func (s *Service) Unsubscribe(ctx context.Context, input Input) error {
+	ctx, span := s.tracer.Start(ctx, "webhook.Service.Unsubscribe")
+	defer span.End()
+
+	subscription, err := s.getSubscription(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteSubscription(ctx, subscription)
+}
+
+func (s *Service) GetDeliveries(ctx context.Context, input Input) ([]value.Delivery, error) {
+	ctx, span := s.tracer.Start(ctx, "webhook.Service.GetDeliveries")
+	defer span.End()
+
+	if err := value.ValidateDeliveryQuery(input.DeliveryQuery); err != nil {
+		return []value.Delivery{}, err
+	}
+
+	subscription, err := s.getSubscription(ctx, input)
+	if err != nil {
+		return []value.Delivery{}, err
+	}
+
+	return s.repo.GetDeliveries(ctx, subscription, input.DeliveryQuery)
+}
+
+func (s *Service) Redeliver(ctx context.Context, input Input) error {
+	ctx, span := s.tracer.Start(ctx, "webhook.Service.Redeliver")
+	defer span.End()
+
+	subscription, err := s.getSubscription(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	if err := value.ValidateId(input.DeliveryId); err != nil {
+		return ErrDeliveryNotFound
+	}
+
+	return s.repo.Redeliver(ctx, subscription, input.DeliveryId)
+}
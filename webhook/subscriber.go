@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/rizface/quora/events"
+	"github.com/rizface/quora/webhook/value"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Subscriber only stores the deliveries of an event, the worker sends them in
+// the background so the request that emitted the event does not wait for the
+// webhooks.
+type Subscriber struct {
+	tracer trace.Tracer
+	repo   *Repository
+}
+
+func NewSubscriber(repo *Repository, tracer trace.Tracer) *Subscriber {
+	return &Subscriber{
+		repo:   repo,
+		tracer: tracer,
+	}
+}
+
+func (s *Subscriber) Register(bus *events.Bus) {
+	events.Subscribe(bus, "webhook.question_created", func(ctx context.Context, tx *sql.Tx, e events.QuestionCreated) error {
+		return s.OnEvent(ctx, tx, e, e.AuthorId, e.SpaceId)
+	})
+	events.Subscribe(bus, "webhook.question_deleted", func(ctx context.Context, tx *sql.Tx, e events.QuestionDeleted) error {
+		return s.OnEvent(ctx, tx, e, e.AuthorId, e.SpaceId)
+	})
+	events.Subscribe(bus, "webhook.answer_created", func(ctx context.Context, tx *sql.Tx, e events.AnswerCreated) error {
+		return s.OnEvent(ctx, tx, e, e.QuestionAuthorId, e.SpaceId)
+	})
+	events.Subscribe(bus, "webhook.answer_voted", func(ctx context.Context, tx *sql.Tx, e events.AnswerVoted) error {
+		return s.OnEvent(ctx, tx, e, e.QuestionAuthorId, e.SpaceId)
+	})
+}
+
+// OnEvent enqueues the event for the subscriptions of the question author and
+// of the space of the question.
+func (s *Subscriber) OnEvent(ctx context.Context, tx *sql.Tx, e events.Event, authorId, spaceId string) error {
+	ctx, span := s.tracer.Start(ctx, "webhook.Subscriber.OnEvent")
+	defer span.End()
+
+	payload, err := json.Marshal(value.NewPayload(e))
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Enqueue(ctx, tx, e.Name(), authorId, spaceId, payload)
+}
@@ -0,0 +1,21 @@
+package value
+
+import "net"
+
+// sharedAddressSpace is the carrier grade NAT range of RFC 6598, it is not
+// reachable from the internet either.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublicIP tells whether a webhook may be sent to ip. Loopback, private and
+// link-local addresses, such as the metadata service of the cloud provider,
+// belong to the network of the app and are refused.
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsUnspecified() ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		sharedAddressSpace.Contains(ip))
+}
@@ -0,0 +1,216 @@
+package value
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/google/uuid"
+	"github.com/rizface/quora/events"
+	"github.com/rizface/quora/nuller"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	// StatusDead is set once every retry failed, only a manual redelivery
+	// sends the payload again
+	StatusDead = "dead"
+
+	secretPrefix = "whsec_"
+)
+
+var httpScheme = regexp.MustCompile(`^https?://`)
+
+// EventTypes lists the events that can be sent to a webhook
+var EventTypes = []string{
+	events.QuestionCreatedName,
+	events.QuestionDeletedName,
+	events.AnswerCreatedName,
+	events.AnswerVotedName,
+}
+
+type (
+	SubscriptionPayload struct {
+		Url        string            `json:"url"`
+		SpaceId    nuller.NullString `json:"spaceId"`
+		EventTypes []string          `json:"eventTypes"`
+	}
+
+	// Subscription without a space receives the events of the questions of
+	// its owner, a subscription with a space receives the events of every
+	// question in the space.
+	Subscription struct {
+		Id         string            `json:"id"`
+		OwnerId    string            `json:"ownerId"`
+		SpaceId    nuller.NullString `json:"spaceId"`
+		Url        string            `json:"url"`
+		Secret     string            `json:"secret,omitempty"`
+		EventTypes []string          `json:"eventTypes"`
+		CreatedAt  time.Time         `json:"createdAt"`
+	}
+
+	// Payload is the body sent to the webhook, Id is the same for every
+	// attempt so receivers can drop deliveries they already processed.
+	Payload struct {
+		Id        string       `json:"id"`
+		Event     string       `json:"event"`
+		CreatedAt time.Time    `json:"createdAt"`
+		Data      events.Event `json:"data"`
+	}
+
+	Delivery struct {
+		Id             string          `json:"id"`
+		SubscriptionId string          `json:"subscriptionId"`
+		EventType      string          `json:"eventType"`
+		Payload        json.RawMessage `json:"payload"`
+		Status         string          `json:"status"`
+		Attempts       int             `json:"attempts"`
+		NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+		LastStatusCode *int            `json:"lastStatusCode"`
+		LastError      *string         `json:"lastError"`
+		DeliveredAt    *time.Time      `json:"deliveredAt"`
+		CreatedAt      time.Time       `json:"createdAt"`
+		// set for the deliveries picked up by the worker
+		Url        string `json:"-"`
+		Secret     string `json:"-"`
+		ClaimToken string `json:"-"`
+	}
+
+	Attempt struct {
+		DeliveryId string
+		ClaimToken string
+		StatusCode int
+		Error      string
+		Duration   time.Duration
+	}
+
+	DeliveryQuery struct {
+		Limit  int
+		Skip   int
+		Status string
+	}
+)
+
+func NewSubscription(p SubscriptionPayload, ownerId string) (Subscription, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return Subscription{}, err
+	}
+
+	eventTypes := []string{}
+	for _, eventType := range p.EventTypes {
+		eventTypes = append(eventTypes, strings.TrimSpace(eventType))
+	}
+
+	return Subscription{
+		Id:         uuid.NewString(),
+		OwnerId:    ownerId,
+		SpaceId:    p.SpaceId,
+		Url:        strings.TrimSpace(p.Url),
+		Secret:     secretPrefix + hex.EncodeToString(secret),
+		EventTypes: eventTypes,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+func ValidateSubscription(s Subscription) error {
+	eventTypes := make([]interface{}, 0, len(EventTypes))
+	for _, eventType := range EventTypes {
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	return validation.Errors{
+		"url": validation.Validate(s.Url,
+			validation.Required,
+			is.URL,
+			validation.Match(httpScheme).Error("must be an http or https url"),
+		),
+		"spaceId":    validation.Validate(s.SpaceId, is.UUID),
+		"eventTypes": validation.Validate(s.EventTypes, validation.Each(validation.Required, validation.In(eventTypes...))),
+	}.Filter()
+}
+
+func NewPayload(e events.Event) Payload {
+	return Payload{
+		Id:        uuid.NewString(),
+		Event:     e.Name(),
+		CreatedAt: time.Now(),
+		Data:      e,
+	}
+}
+
+// Sign returns the signature of the body sent at timestamp, it is the hex
+// encoded HMAC-SHA256 of "timestamp.body" keyed with the subscription secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns the delay before the next attempt, it doubles after every
+// failed attempt up to max.
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	delay := time.Duration(float64(base) * math.Pow(2, float64(attempts-1)))
+	if delay <= 0 || delay > max {
+		return max
+	}
+
+	return delay
+}
+
+func IsSuccessStatus(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
+
+func NewDeliveryQuery(url url.Values) (DeliveryQuery, error) {
+	q := DeliveryQuery{
+		Limit:  20,
+		Skip:   0,
+		Status: url.Get("status"),
+	}
+
+	if url.Has("limit") && url.Get("limit") != "" {
+		limit, err := strconv.Atoi(url.Get("limit"))
+		if err != nil {
+			return DeliveryQuery{}, err
+		}
+
+		q.Limit = limit
+	}
+
+	if url.Has("skip") && url.Get("skip") != "" {
+		skip, err := strconv.Atoi(url.Get("skip"))
+		if err != nil {
+			return DeliveryQuery{}, err
+		}
+
+		q.Skip = skip
+	}
+
+	return q, nil
+}
+
+func ValidateDeliveryQuery(q DeliveryQuery) error {
+	return validation.Errors{
+		"limit":  validation.Validate(q.Limit, validation.Min(1), validation.Max(100)),
+		"skip":   validation.Validate(q.Skip, validation.Min(0)),
+		"status": validation.Validate(q.Status, validation.In(StatusPending, StatusDelivered, StatusDead)),
+	}.Filter()
+}
+
+func ValidateId(id string) error {
+	return validation.Validate(id, validation.Required, is.UUID)
+}
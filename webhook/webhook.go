@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"database/sql"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rizface/quora/events"
+	"github.com/rizface/quora/identifier"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Feature struct {
+	handler *Handler
+	r       *chi.Mux
+	Worker  *Worker
+}
+
+func NewFeature(r *chi.Mux, db *sql.DB, tracer trace.Tracer, bus *events.Bus) *Feature {
+	var (
+		repo         = NewRepository(db, tracer)
+		destinations = NewDestinations()
+		svc          = NewService(repo, destinations, tracer)
+		handler      = NewHandler(svc, tracer)
+	)
+
+	NewSubscriber(repo, tracer).Register(bus)
+
+	return &Feature{
+		handler: handler,
+		r:       r,
+		Worker:  NewWorker(repo, destinations, tracer),
+	}
+}
+
+func (f *Feature) RegisterRoutes() {
+	f.r.Group(func(r chi.Router) {
+		r.Use(identifier.Identifier)
+
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Post("/", f.handler.Subscribe)
+			r.Get("/", f.handler.GetSubscriptions)
+			r.Delete("/{id}", f.handler.Unsubscribe)
+			r.Get("/{id}/deliveries", f.handler.GetDeliveries)
+			r.Post("/{id}/deliveries/{deliveryId}/redeliver", f.handler.Redeliver)
+		})
+	})
+}
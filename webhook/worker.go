@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rizface/quora/periodic"
+	"github.com/rizface/quora/webhook/value"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Worker sends the pending deliveries. Failed deliveries are retried with an
+// exponential backoff until the attempts run out, then they are dead.
+type Worker struct {
+	repo        *Repository
+	tracer      trace.Tracer
+	client      *http.Client
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	maxAttempts int
+	batch       int
+	concurrency int
+	// how long a claimed batch is kept from the other instances
+	lease time.Duration
+
+	loop *periodic.Worker
+}
+
+// NewWorker does not follow redirects, a redirect is the response of the
+// delivery.
+func NewWorker(repo *Repository, destinations *Destinations, tracer trace.Tracer) *Worker {
+	interval, err := time.ParseDuration(os.Getenv("WEBHOOK_DELIVERY_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	baseDelay, err := time.ParseDuration(os.Getenv("WEBHOOK_RETRY_BASE_DELAY"))
+	if err != nil || baseDelay <= 0 {
+		baseDelay = 30 * time.Second
+	}
+
+	maxAttempts, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	if err != nil || maxAttempts <= 0 {
+		maxAttempts = 8
+	}
+
+	var (
+		timeout     = 10 * time.Second
+		batch       = 100
+		concurrency = 8
+		// the batch is sent in rounds of concurrency deliveries, each round
+		// waits for its slowest delivery, plus a round to save the attempts
+		lease = time.Duration((batch+concurrency-1)/concurrency+1) * timeout
+	)
+
+	return &Worker{
+		repo:   repo,
+		tracer: tracer,
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext:         destinations.DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+				MaxIdleConnsPerHost: 2,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		baseDelay:   baseDelay,
+		maxDelay:    6 * time.Hour,
+		maxAttempts: maxAttempts,
+		batch:       batch,
+		concurrency: concurrency,
+		lease:       lease,
+		loop:        periodic.NewWorker(interval),
+	}
+}
+
+func (w *Worker) Start() {
+	w.loop.Start(w.deliver)
+}
+
+func (w *Worker) deliver(ctx context.Context) {
+	ctx, span := w.tracer.Start(ctx, "webhook.Worker.deliver")
+	defer span.End()
+
+	for ctx.Err() == nil {
+		deliveries, err := w.repo.Claim(ctx, w.batch, w.lease)
+		if err != nil {
+			if ctx.Err() == nil {
+				span.RecordError(err)
+				log.Printf("failed claim webhook deliveries: %v", err)
+			}
+
+			return
+		}
+
+		var (
+			wg  sync.WaitGroup
+			sem = make(chan struct{}, w.concurrency)
+		)
+
+		for _, d := range deliveries {
+			sem <- struct{}{}
+			wg.Add(1)
+
+			go func(d value.Delivery) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+
+				w.send(ctx, d)
+			}(d)
+		}
+
+		wg.Wait()
+
+		if len(deliveries) < w.batch {
+			return
+		}
+	}
+}
+
+func (w *Worker) send(ctx context.Context, d value.Delivery) {
+	ctx, span := w.tracer.Start(ctx, "webhook.Worker.send")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("delivery", d.Id),
+		attribute.String("event", d.EventType),
+	)
+
+	var (
+		attempt = value.Attempt{DeliveryId: d.Id, ClaimToken: d.ClaimToken}
+		start   = time.Now()
+	)
+
+	statusCode, err := w.post(ctx, d)
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	attempt.StatusCode = statusCode
+
+	// the worker is stopping, the delivery is sent again once its lease expires
+	if ctx.Err() != nil {
+		return
+	}
+
+	attempt.Duration = time.Since(start)
+
+	var (
+		attempts      = d.Attempts + 1
+		status        = value.StatusPending
+		nextAttemptAt = time.Now().Add(value.Backoff(attempts, w.baseDelay, w.maxDelay))
+	)
+
+	if value.IsSuccessStatus(attempt.StatusCode) {
+		status = value.StatusDelivered
+	} else if attempts >= w.maxAttempts {
+		status = value.StatusDead
+	}
+
+	if attempt.Error == "" && status != value.StatusDelivered {
+		attempt.Error = fmt.Sprintf("unexpected status %d", attempt.StatusCode)
+	}
+
+	if err := w.repo.SaveAttempt(ctx, attempt, status, nextAttemptAt); err != nil {
+		span.RecordError(err)
+		log.Printf("failed save webhook attempt of %s: %v", d.Id, err)
+	}
+}
+
+// post sends the signed payload and returns the status code of the response.
+func (w *Worker) post(ctx context.Context, d value.Delivery) (int, error) {
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Url, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "quora-webhook")
+	req.Header.Set("X-Webhook-Delivery", d.Id)
+	req.Header.Set("X-Webhook-Event", d.EventType)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", value.Sign(d.Secret, timestamp, d.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// drain the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) //nolint:errcheck
+
+	return resp.StatusCode, nil
+}
+
+func (w *Worker) Stop(ctx context.Context) error {
+	return w.loop.Stop(ctx)
+}